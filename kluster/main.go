@@ -1,72 +1,380 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
 	klient "kluster/pkg/client/clientset/versioned"
-	kinfFac "kluster/pkg/client/informers/externalversions"
+	"kluster/pkg/config"
 	"kluster/pkg/controller"
+	"kluster/pkg/credentials"
+	"kluster/pkg/do"
+	"kluster/pkg/health"
+	"kluster/pkg/metrics"
+	"kluster/pkg/provider"
+	"kluster/pkg/provider/fake"
+	"kluster/pkg/scope"
+	"kluster/pkg/webhook"
 
+	"k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	"k8s.io/klog/v2"
 )
 
-// Define a global flag to save the log to file or to stdout
-var LogToStderr bool
+// Flags of the controller, each of them can also be set by the environment variable KLUSTER_<NAME>,
+// e.g. KLUSTER_RESYNC_PERIOD, or in the YAML file given by -config
+var (
+	configFile      = flag.String("config", "", "YAML file that maps flag names to values, the command line and the environment take precedence")
+	kubeconfig      = flag.String("kubeconfig", "", "kubeconfig of the cluster the klusters live in, empty uses $KUBECONFIG, ~/.kube/config or the in-cluster config")
+	workers         = flag.Int("workers", 3, "number of klusters reconciled in parallel")
+	resyncPeriod    = flag.Duration("resync-period", 10*time.Minute, "how often every kluster is reconciled again, which checks its cloud cluster for drift")
+	watchNamespaces = flag.String("namespaces", "", "comma separated namespaces to manage the klusters of, empty manages all namespaces")
+	watchNamespace  = flag.String("namespace", "", "deprecated, use -namespaces")
+	labelSelector   = flag.String("label-selector", "", "only manage the klusters that match this label selector, e.g. team=a")
+	providerTimeout = flag.Duration("provider-timeout", 2*time.Minute, "time one reconcile may spend calling the cloud, 0 means no limit")
+	shutdownTimeout = flag.Duration("shutdown-timeout", 30*time.Second, "time the workers get on SIGTERM to finish the queued klusters before their calls to the cloud are cancelled")
+)
 
-func main() {
+// Flags to run the controller against the in-memory fake provider instead of a cloud account
+var (
+	fakeProvider          = flag.Bool("fake-provider", false, "simulate clusters in memory instead of calling the cloud provider APIs")
+	fakeProvisioningDelay = flag.Duration("fake-provisioning-delay", 30*time.Second, "time a fake cluster stays in provisioning")
+	fakeDeletionDelay     = flag.Duration("fake-deletion-delay", 10*time.Second, "time a fake cluster stays in deleting")
+	fakeUpgradeDelay      = flag.Duration("fake-upgrade-delay", 30*time.Second, "time a fake cluster stays in upgrading")
+	fakeFailureRate       = flag.Float64("fake-failure-rate", 0, "probability in [0, 1] that a fake provider call fails")
+)
+
+// Flags of where the provider tokens are read from, klusters without spec.tokenSecret and spec.credentials use
+// the token of -token-file, or else the one of the environment variable -token-env
+var (
+	tokenSecretKey      = flag.String("token-secret-key", credentials.DefaultKey, "key of the token in the secrets that do not name one")
+	tokenSecretSelector = flag.String("token-secret-selector", "", "label selector of the secrets that are cached, other token secrets are read from the API server, empty caches all secrets")
+	tokenFile           = flag.String("token-file", "", "file with the default token, e.g. mounted by a CSI secret store")
+	tokenEnv            = flag.String("token-env", "DIGITALOCEAN_TOKEN", "environment variable with the default token, used when it is set and there is no -token-file")
+)
 
-	LogToStderr = false
+// Override of the digital ocean API URL, e.g. to run against a doserver
+var doAPIURL = flag.String("do-api-url", "", "base URL of the digital ocean API, empty uses the public API")
 
-	if LogToStderr {
-		klog.InitFlags(nil)
-		flag.Set("log_file", "./log")
-	}
+// Flags of the admission webhook server, it only runs when a certificate directory is given
+var (
+	webhookAddr    = flag.String("webhook-addr", ":9443", "address the admission webhooks are served on")
+	webhookCertDir = flag.String("webhook-cert-dir", "", "directory with tls.crt and tls.key of the webhook server, empty disables the webhooks")
+)
+
+// Address the prometheus metrics are served on under /metrics, empty disables them
+var metricsAddr = flag.String("metrics-addr", ":8080", "address the prometheus metrics are served on, empty disables them")
 
-	flag.Set("logtostderr", "false")
-	flag.Parse()
+// Flags of the health probes of the kubelet, served on their own address
+var (
+	healthProbeAddr    = flag.String("health-probe-addr", ":8081", "address /healthz and /readyz are served on, empty disables them")
+	workerStuckTimeout = flag.Duration("worker-stuck-timeout", 10*time.Minute, "how long a worker may process one kluster before /healthz fails")
+)
+
+// Flags of the leader election, only the replica holding the lease runs the workers
+var (
+	leaderElect          = flag.Bool("leader-elect", true, "run the workers only while holding the leader lease, so that several replicas can run")
+	leaderElectNamespace = flag.String("leader-elect-namespace", os.Getenv("POD_NAMESPACE"), "namespace of the leader lease, defaults to $POD_NAMESPACE or default")
+	leaderElectName      = flag.String("leader-elect-name", "kluster-controller", "name of the leader lease")
+	leaseDuration        = flag.Duration("leader-elect-lease-duration", 15*time.Second, "how long other replicas wait before taking over a lease that was not renewed")
+	renewDeadline        = flag.Duration("leader-elect-renew-deadline", 10*time.Second, "how long the leader keeps trying to renew the lease before it steps down")
+	retryPeriod          = flag.Duration("leader-elect-retry-period", 2*time.Second, "how often replicas try to acquire or renew the lease")
+)
+
+// Defaults filled into klusters by the mutating webhook
+var (
+	defaultRegion        = flag.String("default-region", "nyc1", "region of klusters without spec.region")
+	defaultVersion       = flag.String("default-version", "latest", "version of klusters without spec.version")
+	defaultNodeSize      = flag.String("default-node-size", "s-2vcpu-2gb", "size of node pools without a size")
+	defaultNodePool      = flag.String("default-node-pool", "default-pool", "name of the node pool of klusters without node pools, empty adds none")
+	defaultNodePoolCount = flag.Int("default-node-pool-count", 3, "node count of the default node pool")
+)
+
+func main() {
+	// Log flags like -v and -logtostderr are set like any other flag
+	klog.InitFlags(nil)
+	if err := config.Load(flag.CommandLine, os.Args[1:], "config"); err != nil {
+		klog.Fatalf("error %s, loading the configuration", err.Error())
+	}
+	defer klog.Flush()
+	if *configFile != "" {
+		klog.Infof("loaded the configuration from %s\n", *configFile)
+	}
 
-	// Read kubeconfig (yaml) file to build kubenetes configuration from file
-	kubeconfig := flag.String("kubeconfig", "/Users/lisiqi/.kube/config", "location to your kubeconfig file")
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	// Read kubeconfig (yaml) file to build kubenetes configuration from file, the in-cluster config is used when there is none
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = *kubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{}).ClientConfig()
 	if err != nil {
-		// Handle error if kubeconfig failed to build
-		klog.Errorf("erorr %s building config from flags, trying to build inclusterconfig\n", err.Error())
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			klog.Errorf("error %s, getting inclusterconfig", err.Error())
-		}
+		klog.Fatalf("error %s, building the client config", err.Error())
 	}
 	// Create clientset to manage resources and monitor the state of the cluster
-	klientset, err := klient.NewForConfig(config)
+	klientset, err := klient.NewForConfig(restConfig)
 	if err != nil {
-		// Handle error if client set failed to build
-		klog.Errorf("error %s, creating klientset\n", err.Error())
+		klog.Fatalf("error %s, creating klientset\n", err.Error())
 	}
 
-	client, err := kubernetes.NewForConfig(config)
+	client, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		klog.Fatalf("error %s, getting std client\n", err.Error())
+	}
+	namespaces, err := parseNamespaces(*watchNamespaces, *watchNamespace)
 	if err != nil {
-		// Handle error if client set failed to build
-		klog.Errorf("error %s, getting std client\n", err.Error())
+		klog.Fatalf("error %s, parsing -namespaces", err.Error())
+	}
+	if _, err := labels.Parse(*labelSelector); err != nil {
+		klog.Fatalf("error %s, parsing -label-selector", err.Error())
+	}
+	if _, err := labels.Parse(*tokenSecretSelector); err != nil {
+		klog.Fatalf("error %s, parsing -token-secret-selector", err.Error())
+	}
+	if len(namespaces) > 0 {
+		klog.Infof("managing the klusters of the namespaces %s\n", strings.Join(namespaces, ", "))
 	}
 	// Create informers to cache reosurces and call k8s API. It watches for updates to k8s resources (add/delete)
 	// They keep in-mem local cache of resources, which can be retrieved by a given index.
-	// They refresh the cache using two mechanisms: List and Watch. Here the sync period is every 10 minutes.
-	// The reason we use shared factory is so that one informer instance is shared for all namespaces.
-	informers := kinfFac.NewSharedInformerFactory(klientset, 10*time.Minute)
+	// They refresh the cache using two mechanisms: List and Watch. The sync period is set by -resync-period.
+	// Every namespace of -namespaces gets its own shared factories, so that a Role in each of them is enough,
+	// without -namespaces one set of informers is shared for all namespaces.
+	// The token secrets are watched so that klusters are reconciled again when their secret changes,
+	// with -token-secret-selector only the matching ones are cached.
+	informers := scope.New(klientset, client, namespaces, *labelSelector, *tokenSecretSelector, *resyncPeriod)
 
 	// Create controller that includes params passed from the clientset and the informer (with local cache of resources and lister)
-	c := controller.NewController(client, klientset, informers.Siqi().V1alpha1().Klusters())
-	ch := make(chan struct{})
+	// Register the cloud providers that can be selected by spec.provider
+	providers := provider.Registry{
+		do.Name: do.New(*doAPIURL),
+	}
+	if *fakeProvider {
+		// Serve every provider name with the same fake so existing manifests run offline
+		f := fake.New(fake.Options{
+			ProvisioningDelay: *fakeProvisioningDelay,
+			DeletionDelay:     *fakeDeletionDelay,
+			UpgradeDelay:      *fakeUpgradeDelay,
+			FailureRate:       *fakeFailureRate,
+		})
+		klog.Infof("using the fake provider, no cloud clusters will be created")
+		providers = provider.Registry{
+			do.Name:   f,
+			fake.Name: f,
+		}
+	}
+
+	// Record the latency and result of every call to the cloud, and reuse the client of a kluster until its credential source changes
+	providers = provider.Cache(metrics.Instrument(providers))
+
+	if *webhookCertDir != "" {
+		server := &webhook.Server{
+			Addr:    *webhookAddr,
+			CertDir: *webhookCertDir,
+			Defaults: webhook.Defaults{
+				Region:   *defaultRegion,
+				Version:  *defaultVersion,
+				NodeSize: *defaultNodeSize,
+				NodePool: v1alpha1.NodePool{
+					Name:  *defaultNodePool,
+					Size:  *defaultNodeSize,
+					Count: *defaultNodePoolCount,
+				},
+			},
+			// The informers of the grants are started together with the ones of the klusters
+			Grants:  informers.GrantLister(),
+			Manages: informers.Contains,
+		}
+		go func() {
+			if err := server.Start(); err != nil {
+				klog.Errorf("error %s, serving webhooks", err.Error())
+			}
+		}()
+	}
 
-	// Start informers, handled in goroutine chanels
-	informers.Start(ch)
-	// Run controlelrs, running workers in parallel to handle events in passed channels
-	if err = c.Run(3, ch); err != nil {
-		klog.Errorf("Error running controller: %s", err.Error())
+	var defaultCredentials credentials.Source
+	switch {
+	case *tokenFile != "":
+		defaultCredentials = &credentials.File{Path: *tokenFile}
+	case *tokenEnv != "" && os.Getenv(*tokenEnv) != "":
+		defaultCredentials = &credentials.Env{Name: *tokenEnv}
 	}
+	if defaultCredentials != nil {
+		klog.Infof("klusters without a token secret use the token of the %s\n", defaultCredentials)
+	}
+
+	c := controller.NewController(client, klientset, informers, providers, controller.Options{
+		ProviderTimeout:    *providerTimeout,
+		ShutdownTimeout:    *shutdownTimeout,
+		TokenSecretKey:     *tokenSecretKey,
+		DefaultCredentials: defaultCredentials,
+	})
 
+	if *metricsAddr != "" {
+		metrics.RegisterKlusters(informers.KlusterLister())
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler())
+			klog.Infof("serving metrics on %s\n", *metricsAddr)
+			if err := http.ListenAndServe(*metricsAddr, mux); err != nil {
+				klog.Errorf("error %s, serving metrics", err.Error())
+			}
+		}()
+	}
+
+	// Stop on SIGTERM, which drains the workqueue and then gives up the lease instead of letting it expire
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start informers, handled in goroutine chanels.
+	// Replicas waiting for the lease keep their cache warm so that they take over quickly.
+	informers.Start(ctx.Done())
+
+	// The workers stop on SIGTERM and when the lease is lost, run returns once they are drained
+	stopped := make(chan struct{})
+	run := func(leaderCtx context.Context) {
+		defer close(stopped)
+		runCtx, cancel := context.WithCancel(leaderCtx)
+		defer cancel()
+		go func() {
+			select {
+			case <-ctx.Done():
+				cancel()
+			case <-runCtx.Done():
+			}
+		}()
+		// Run controlelrs, running workers in parallel to handle events in passed channels
+		if err := c.Run(runCtx, *workers); err != nil {
+			klog.Errorf("Error running controller: %s", err.Error())
+		}
+	}
+
+	liveness := []health.Check{
+		{Name: "workers", Check: func() error { return c.Healthy(*workerStuckTimeout) }},
+	}
+	readiness := []health.Check{
+		{Name: "informers", Check: c.Ready},
+	}
+
+	var le *leaderelection.LeaderElector
+	if *leaderElect {
+		le = newLeaderElector(ctx, client, run)
+		// The leader fails the liveness probe when it could not renew the lease for a lease duration
+		liveness = append(liveness, health.Check{Name: "leader-election", Check: func() error { return le.Check(*leaseDuration) }})
+		readiness = append(readiness, health.Check{Name: "leader-election", Check: func() error {
+			if le.GetLeader() == "" {
+				return fmt.Errorf("no leader of the lease %s was observed yet", *leaderElectName)
+			}
+			return nil
+		}})
+	}
+
+	if *healthProbeAddr != "" {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle(health.LivenessPath, health.Handler(liveness...))
+			mux.Handle(health.ReadinessPath, health.Handler(readiness...))
+			klog.Infof("serving health probes on %s\n", *healthProbeAddr)
+			if err := http.ListenAndServe(*healthProbeAddr, mux); err != nil {
+				klog.Errorf("error %s, serving health probes", err.Error())
+			}
+		}()
+	}
+
+	if le == nil {
+		run(ctx)
+		return
+	}
+	// The lease is only given up once the workers are drained, so that the next leader never runs next to them
+	leCtx, cancelLE := context.WithCancel(context.Background())
+	go func() {
+		<-ctx.Done()
+		if le.IsLeader() {
+			<-stopped
+		}
+		cancelLE()
+	}()
+	klog.Infof("waiting for the leader lease %s\n", *leaderElectName)
+	le.Run(leCtx)
+}
+
+// The namespaces of -namespaces and of the deprecated -namespace, none means all namespaces
+func parseNamespaces(list, single string) ([]string, error) {
+	namespaces := []string{}
+	seen := map[string]bool{}
+	for _, ns := range append(strings.Split(list, ","), single) {
+		ns = strings.TrimSpace(ns)
+		if ns == "" || seen[ns] {
+			continue
+		}
+		if msgs := validation.ValidateNamespaceName(ns, false); len(msgs) > 0 {
+			return nil, fmt.Errorf("namespace %q: %s", ns, strings.Join(msgs, ", "))
+		}
+		seen[ns] = true
+		namespaces = append(namespaces, ns)
+	}
+	return namespaces, nil
+}
+
+// Create the leader election that runs the workers while this replica holds the leader lease
+func newLeaderElector(ctx context.Context, client kubernetes.Interface, run func(ctx context.Context)) *leaderelection.LeaderElector {
+	namespace := *leaderElectNamespace
+	if namespace == "" {
+		namespace = "default"
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		klog.Fatalf("error %s, getting the hostname for the leader election", err.Error())
+	}
+	// The uid keeps the identity unique when replicas share a hostname
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      *leaderElectName,
+			Namespace: namespace,
+		},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	klog.Infof("leader election for the lease %s/%s as %s\n", namespace, *leaderElectName, identity)
+	le, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   *leaseDuration,
+		RenewDeadline:   *renewDeadline,
+		RetryPeriod:     *retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            *leaderElectName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: run,
+			OnStoppedLeading: func() {
+				if ctx.Err() != nil {
+					klog.Infof("stepped down as leader\n")
+					return
+				}
+				// Another replica may already run the workers, keep going would create duplicate clusters
+				klog.Fatalf("lost the leader lease")
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					klog.Infof("%s is the leader\n", current)
+				}
+			},
+		},
+	})
+	if err != nil {
+		klog.Fatalf("error %s, creating the leader election", err.Error())
+	}
+	return le
 }
@@ -0,0 +1,179 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionAnnotation keeps the fields one version can not hold, so that they survive a round trip through the other version
+const ConversionAnnotation = "siqi.dev/conversion"
+
+// The fields a conversion lost, they are restored when the kluster is converted back
+type conversionData struct {
+	// TokenSecretNamespaceDefaulted is set when the v1beta1 tokenSecretRef had no namespace and the
+	// v1alpha1 tokenSecret got the namespace of the kluster
+	TokenSecretNamespaceDefaulted bool `json:"tokenSecretNamespaceDefaulted,omitempty"`
+	// NodePools are the minNodes and maxNodes of the v1alpha1 node pools without autoScale
+	NodePools []nodePoolBounds `json:"nodePools,omitempty"`
+}
+
+type nodePoolBounds struct {
+	Name     string `json:"name"`
+	MinNodes int    `json:"minNodes,omitempty"`
+	MaxNodes int    `json:"maxNodes,omitempty"`
+}
+
+// Read the conversion annotation, one that can not be parsed is ignored so that the kluster can still be read
+func readConversionData(meta *metav1.ObjectMeta) conversionData {
+	data := conversionData{}
+	if raw, ok := meta.Annotations[ConversionAnnotation]; ok {
+		_ = json.Unmarshal([]byte(raw), &data)
+	}
+	return data
+}
+
+// Replace the conversion annotation of the converted kluster, it is removed when nothing was lost
+func writeConversionData(meta *metav1.ObjectMeta, data conversionData) error {
+	annotations := map[string]string{}
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+	delete(annotations, ConversionAnnotation)
+	if data.TokenSecretNamespaceDefaulted || len(data.NodePools) > 0 {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		annotations[ConversionAnnotation] = string(raw)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	meta.Annotations = annotations
+	return nil
+}
+
+// ConvertTo converts the kluster to the v1beta1 hub version
+func (src *Kluster) ConvertTo(dst *v1beta1.Kluster) error {
+	dst.ObjectMeta = src.ObjectMeta
+	dst.APIVersion = v1beta1.SchemeGroupVersion.String()
+	dst.Kind = "Kluster"
+
+	dst.Spec = v1beta1.KlusterSpec{
+		Name:        src.Spec.Name,
+		Region:      src.Spec.Region,
+		Version:     src.Spec.Version,
+		Provider:    src.Spec.Provider,
+		DriftPolicy: src.Spec.DriftPolicy,
+		Credentials: src.Spec.Credentials,
+	}
+	restore := readConversionData(&src.ObjectMeta)
+	lost := conversionData{}
+	if src.Spec.TokenSecret != "" {
+		ref := &v1beta1.SecretReference{Name: src.Spec.TokenSecret}
+		if namespace, name, ok := strings.Cut(src.Spec.TokenSecret, "/"); ok {
+			ref = &v1beta1.SecretReference{Namespace: namespace, Name: name}
+		}
+		// The namespace was filled in by ConvertFrom
+		if restore.TokenSecretNamespaceDefaulted && ref.Namespace == src.Namespace {
+			ref.Namespace = ""
+		}
+		ref.Key = src.Spec.TokenSecretKey
+		dst.Spec.TokenSecretRef = ref
+	}
+	for _, np := range src.Spec.NodePools {
+		pool := v1beta1.NodePool{Name: np.Name, Size: np.Size, Count: np.Count}
+		if np.AutoScale {
+			pool.Autoscaling = &v1beta1.NodePoolAutoscaling{MinNodes: np.MinNodes, MaxNodes: np.MaxNodes}
+		} else if np.MinNodes != 0 || np.MaxNodes != 0 {
+			// v1beta1 only has bounds for autoscaled pools
+			lost.NodePools = append(lost.NodePools, nodePoolBounds{Name: np.Name, MinNodes: np.MinNodes, MaxNodes: np.MaxNodes})
+		}
+		dst.Spec.NodePools = append(dst.Spec.NodePools, pool)
+	}
+	if err := writeConversionData(&dst.ObjectMeta, lost); err != nil {
+		return err
+	}
+
+	dst.Status = v1beta1.KlusterStatus{
+		KlusterID:           src.Status.KlusterID,
+		Progress:            src.Status.Progress,
+		KubeConfigSecret:    src.Status.KubeConfigSecret,
+		KubeConfigExpiresAt: src.Status.KubeConfigExpiresAt,
+		ObservedGeneration:  src.Status.ObservedGeneration,
+		LastError:           src.Status.LastError,
+		LastErrorTime:       src.Status.LastErrorTime,
+		LastReconcileTime:   src.Status.LastReconcileTime,
+		Conditions:          src.Status.Conditions,
+	}
+	for _, np := range src.Status.NodePools {
+		dst.Status.NodePools = append(dst.Status.NodePools, v1beta1.NodePoolStatus(np))
+	}
+	return nil
+}
+
+// ConvertFrom converts the v1beta1 hub version to this kluster
+func (dst *Kluster) ConvertFrom(src *v1beta1.Kluster) error {
+	dst.ObjectMeta = src.ObjectMeta
+	dst.APIVersion = SchemeGroupVersion.String()
+	dst.Kind = "Kluster"
+
+	dst.Spec = KlusterSpec{
+		Name:        src.Spec.Name,
+		Region:      src.Spec.Region,
+		Version:     src.Spec.Version,
+		Provider:    src.Spec.Provider,
+		DriftPolicy: src.Spec.DriftPolicy,
+		Credentials: src.Spec.Credentials,
+	}
+	restore := readConversionData(&src.ObjectMeta)
+	lost := conversionData{}
+	if ref := src.Spec.TokenSecretRef; ref != nil {
+		// An empty namespace is the namespace of the kluster
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = src.Namespace
+			lost.TokenSecretNamespaceDefaulted = true
+		}
+		dst.Spec.TokenSecret = namespace + "/" + ref.Name
+		dst.Spec.TokenSecretKey = ref.Key
+	}
+	for _, np := range src.Spec.NodePools {
+		pool := NodePool{Name: np.Name, Size: np.Size, Count: np.Count}
+		if np.Autoscaling != nil {
+			pool.AutoScale = true
+			pool.MinNodes = np.Autoscaling.MinNodes
+			pool.MaxNodes = np.Autoscaling.MaxNodes
+		}
+		for _, bounds := range restore.NodePools {
+			if np.Autoscaling == nil && bounds.Name == np.Name {
+				pool.MinNodes = bounds.MinNodes
+				pool.MaxNodes = bounds.MaxNodes
+			}
+		}
+		dst.Spec.NodePools = append(dst.Spec.NodePools, pool)
+	}
+	if err := writeConversionData(&dst.ObjectMeta, lost); err != nil {
+		return err
+	}
+
+	dst.Status = KlsuterStatus{
+		KlusterID:           src.Status.KlusterID,
+		Progress:            src.Status.Progress,
+		KubeConfigSecret:    src.Status.KubeConfigSecret,
+		KubeConfigExpiresAt: src.Status.KubeConfigExpiresAt,
+		ObservedGeneration:  src.Status.ObservedGeneration,
+		LastError:           src.Status.LastError,
+		LastErrorTime:       src.Status.LastErrorTime,
+		LastReconcileTime:   src.Status.LastReconcileTime,
+		Conditions:          src.Status.Conditions,
+	}
+	for _, np := range src.Status.NodePools {
+		dst.Status.NodePools = append(dst.Status.NodePools, NodePoolStatus(np))
+	}
+	return nil
+}
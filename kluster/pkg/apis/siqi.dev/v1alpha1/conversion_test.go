@@ -0,0 +1,136 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1beta1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRoundTripFromV1alpha1(t *testing.T) {
+	tests := []struct {
+		name string
+		spec KlusterSpec
+	}{
+		{
+			name: "autoscaled pool",
+			spec: KlusterSpec{
+				Name:      "k",
+				NodePools: []NodePool{{Name: "a", Size: "s", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 5}},
+			},
+		},
+		{
+			name: "bounds without autoScale",
+			spec: KlusterSpec{
+				Name: "k",
+				NodePools: []NodePool{
+					{Name: "a", Size: "s", Count: 2, MinNodes: 1, MaxNodes: 5},
+					{Name: "b", Size: "s", Count: 3, AutoScale: true, MinNodes: 2, MaxNodes: 4},
+					{Name: "c", Size: "s", Count: 1, MaxNodes: 3},
+				},
+			},
+		},
+		{
+			name: "token secret of the kluster namespace",
+			spec: KlusterSpec{Name: "k", TokenSecret: "team-a/dosecret", TokenSecretKey: "key"},
+		},
+		{
+			name: "token secret of another namespace",
+			spec: KlusterSpec{Name: "k", TokenSecret: "team-b/dosecret"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a", Annotations: map[string]string{"other": "kept"}},
+				Spec:       tt.spec,
+			}
+			hub := &v1beta1.Kluster{}
+			if err := src.DeepCopy().ConvertTo(hub); err != nil {
+				t.Fatalf("ConvertTo: %v", err)
+			}
+			dst := &Kluster{}
+			if err := dst.ConvertFrom(hub); err != nil {
+				t.Fatalf("ConvertFrom: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(dst.Spec, src.Spec) {
+				t.Errorf("spec after round trip = %+v, want %+v", dst.Spec, src.Spec)
+			}
+			if !equality.Semantic.DeepEqual(dst.Annotations, src.Annotations) {
+				t.Errorf("annotations after round trip = %v, want %v", dst.Annotations, src.Annotations)
+			}
+		})
+	}
+}
+
+func TestRoundTripFromV1beta1(t *testing.T) {
+	tests := []struct {
+		name string
+		spec v1beta1.KlusterSpec
+	}{
+		{
+			name: "token secret without namespace",
+			spec: v1beta1.KlusterSpec{Name: "k", TokenSecretRef: &v1beta1.SecretReference{Name: "dosecret", Key: "key"}},
+		},
+		{
+			name: "token secret with the kluster namespace",
+			spec: v1beta1.KlusterSpec{Name: "k", TokenSecretRef: &v1beta1.SecretReference{Namespace: "team-a", Name: "dosecret"}},
+		},
+		{
+			name: "token secret of another namespace",
+			spec: v1beta1.KlusterSpec{Name: "k", TokenSecretRef: &v1beta1.SecretReference{Namespace: "team-b", Name: "dosecret"}},
+		},
+		{
+			name: "node pools",
+			spec: v1beta1.KlusterSpec{
+				Name: "k",
+				NodePools: []v1beta1.NodePool{
+					{Name: "a", Size: "s", Count: 2},
+					{Name: "b", Size: "s", Count: 3, Autoscaling: &v1beta1.NodePoolAutoscaling{MinNodes: 2, MaxNodes: 4}},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := &v1beta1.Kluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a"},
+				Spec:       tt.spec,
+			}
+			spoke := &Kluster{}
+			if err := spoke.ConvertFrom(src.DeepCopy()); err != nil {
+				t.Fatalf("ConvertFrom: %v", err)
+			}
+			dst := &v1beta1.Kluster{}
+			if err := spoke.ConvertTo(dst); err != nil {
+				t.Fatalf("ConvertTo: %v", err)
+			}
+			if !equality.Semantic.DeepEqual(dst.Spec, src.Spec) {
+				t.Errorf("spec after round trip = %+v, want %+v", dst.Spec, src.Spec)
+			}
+			if len(dst.Annotations) != 0 {
+				t.Errorf("annotations after round trip = %v, want none", dst.Annotations)
+			}
+		})
+	}
+}
+
+func TestConvertFromDefaultsTokenSecretNamespace(t *testing.T) {
+	src := &v1beta1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a"},
+		Spec:       v1beta1.KlusterSpec{TokenSecretRef: &v1beta1.SecretReference{Name: "dosecret"}},
+	}
+	dst := &Kluster{}
+	if err := dst.ConvertFrom(src); err != nil {
+		t.Fatalf("ConvertFrom: %v", err)
+	}
+	// The controller reads the namespace/name form of v1alpha1
+	if dst.Spec.TokenSecret != "team-a/dosecret" {
+		t.Errorf("tokenSecret = %q, want team-a/dosecret", dst.Spec.TokenSecret)
+	}
+	if src.Annotations != nil {
+		t.Errorf("ConvertFrom changed the annotations of its source: %v", src.Annotations)
+	}
+}
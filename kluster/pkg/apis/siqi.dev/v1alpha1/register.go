@@ -25,7 +25,7 @@ func init() {
 }
 
 func addKnownTypes(scheme *runtime.Scheme) error {
-	scheme.AddKnownTypes(SchemeGroupVersion, &Kluster{}, &KlusterList{})
+	scheme.AddKnownTypes(SchemeGroupVersion, &Kluster{}, &KlusterList{}, &KlusterCredentials{}, &KlusterCredentialsList{}, &KlusterReferenceGrant{}, &KlusterReferenceGrantList{})
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -7,35 +7,137 @@ import (
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.status.klusterID`
 // +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
 type Kluster struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   KlusterSpec   `json:"spec,omitempty"`
+	// +kubebuilder:validation:Required
+	Spec   KlusterSpec   `json:"spec"`
 	Status KlsuterStatus `json:"status,omitempty"`
 }
 
 type KlsuterStatus struct {
-	KlusterID  string `json:"klusterID,omitempty"`
-	Progress   string `json:"progress,omitempty"`
-	KubeConfig string `json:"kubeConfig,omitempty"`
+	KlusterID string `json:"klusterID,omitempty"`
+	Progress  string `json:"progress,omitempty"`
+
+	// KubeConfigSecret is the name of the secret in the kluster's namespace that holds the
+	// kubeconfig of the cloud cluster under the "kubeconfig" key
+	KubeConfigSecret string `json:"kubeConfigSecret,omitempty"`
+	// KubeConfigExpiresAt is when the credentials in the kubeconfig expire, the controller refreshes them before
+	KubeConfigExpiresAt *metav1.Time `json:"kubeConfigExpiresAt,omitempty"`
+
+	// NodePools is the state of each node pool in the cloud
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastError is the error of the last failed reconcile, it is cleared by a successful one
+	LastError     string       `json:"lastError,omitempty"`
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// LastReconcileTime is when the kluster was last reconciled successfully
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// Conditions are the latest observations of the kluster's state
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// Condition types of a kluster
+const (
+	// The cloud cluster is running and matches the spec
+	ConditionReady = "Ready"
+	// The cloud cluster is being created
+	ConditionProvisioning = "Provisioning"
+	// The cloud cluster is running with problems or failed
+	ConditionDegraded = "Degraded"
+	// The cloud cluster is being deleted
+	ConditionDeleting = "Deleting"
+	// The cloud cluster is being upgraded to spec.version, or the upgrade was refused
+	ConditionUpgrading = "Upgrading"
+	// The cloud cluster was changed outside of the kluster and no longer matches the spec
+	ConditionDrifted = "Drifted"
+	// The provider accepted the credentials of the kluster
+	ConditionCredentialsValid = "CredentialsValid"
+)
+
+// Drift policies of a kluster
+const (
+	DriftPolicyCorrect = "Correct"
+	DriftPolicyReport  = "Report"
+)
+
+// AllNamespaces in allowedNamespaces of a KlusterCredentials lets the klusters of every namespace use it
+const AllNamespaces = "*"
+
+// Node pool states reported in status
+const (
+	NodePoolProvisioning = "provisioning"
+	NodePoolScaling      = "scaling"
+	NodePoolReady        = "ready"
+)
+
+type NodePoolStatus struct {
+	Name       string `json:"name,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Size       string `json:"size,omitempty"`
+	Count      int    `json:"count,omitempty"`
+	ReadyNodes int    `json:"readyNodes,omitempty"`
+	State      string `json:"state,omitempty"`
 }
 
 type KlusterSpec struct {
-	Name        string `json:"name,omitempty"`
-	Region      string `json:"region,omitempty"`
-	Version     string `json:"version,omitempty"`
+	// Name of the cloud cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+	// Region slug of the cloud cluster, like nyc1
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z]+[0-9]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
+	Region  string `json:"region"`
+	Version string `json:"version,omitempty"`
+	// TokenSecret is the secret with the provider token in the form namespace/name
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-.a-z0-9]*[a-z0-9])?$`
 	TokenSecret string `json:"tokenSecret,omitempty"`
+	// TokenSecretKey is the key of the token in the token secret, defaults to the -token-secret-key of the controller
+	TokenSecretKey string `json:"tokenSecretKey,omitempty"`
+	// Credentials is the name of the KlusterCredentials the token is read from instead of a token secret.
+	// Without both the controller uses its default token.
+	Credentials string `json:"credentials,omitempty"`
+	// Provider is the cloud provider the cluster is created in, defaults to digitalocean
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="provider is immutable"
+	Provider string `json:"provider,omitempty"`
+	// DriftPolicy decides what happens when the cloud cluster is changed outside of the kluster,
+	// Correct (the default) brings it back to the spec and Report only sets the Drifted condition
+	// +kubebuilder:validation:Enum=Correct;Report
+	DriftPolicy string `json:"driftPolicy,omitempty"`
 
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
 	NodePools []NodePool `json:"nodePools,omitempty"`
 }
 
 type NodePool struct {
-	Size  string `json:"size,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Count int    `json:"count,omitempty"`
+	// Size of the droplets, it can not be changed, add a node pool with the new size and remove the old one instead
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="size is immutable, replace the node pool to change it"
+	Size string `json:"size,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=1
+	Count int `json:"count,omitempty"`
+
+	// AutoScale lets the provider resize the pool between MinNodes and MaxNodes, Count is only the initial size then
+	AutoScale bool `json:"autoScale,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MinNodes int `json:"minNodes,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	MaxNodes int `json:"maxNodes,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -45,3 +147,94 @@ type KlusterList struct {
 
 	Items []Kluster `json:"items,omitempty"`
 }
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.spec.secretRef.name`
+// +kubebuilder:printcolumn:name="File",type=string,JSONPath=`.spec.file`
+// +kubebuilder:printcolumn:name="Env",type=string,JSONPath=`.spec.env`
+type KlusterCredentials struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Spec KlusterCredentialsSpec `json:"spec"`
+}
+
+// KlusterCredentialsSpec is a provider token that klusters of any allowed namespace reference by name,
+// so that the namespaces do not need a copy of the secret. Exactly one source of the token is set.
+// +kubebuilder:validation:XValidation:rule="[has(self.secretRef), has(self.file), has(self.env)].filter(x, x).size() == 1",message="exactly one of secretRef, file and env must be set"
+type KlusterCredentialsSpec struct {
+	// SecretRef is the secret with the token
+	SecretRef *CredentialsSecretReference `json:"secretRef,omitempty"`
+	// File is the path of a file with the token in the controller pod, e.g. mounted by a CSI secret store
+	File string `json:"file,omitempty"`
+	// Env is the environment variable of the controller that holds the token
+	Env string `json:"env,omitempty"`
+	// AllowedNamespaces are the namespaces whose klusters may use the credentials, "*" allows all of them.
+	// The credentials can not be used by any kluster when it is empty.
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+}
+
+// CredentialsSecretReference points to the key of a secret with the token
+type CredentialsSecretReference struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the token in the secret, defaults to the -token-secret-key of the controller
+	Key string `json:"key,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterCredentialsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterCredentials `json:"items,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Spec KlusterReferenceGrantSpec `json:"spec"`
+}
+
+// KlusterReferenceGrantSpec lets klusters of other namespaces reference secrets in the namespace of the grant
+// as their spec.tokenSecret. Klusters can always reference the secrets of their own namespace.
+type KlusterReferenceGrantSpec struct {
+	// From are the namespaces whose klusters may reference the secrets
+	// +kubebuilder:validation:MinItems=1
+	From []ReferenceGrantFrom `json:"from"`
+	// To are the secrets that may be referenced, all secrets of the namespace when empty
+	To []ReferenceGrantTo `json:"to,omitempty"`
+}
+
+type ReferenceGrantFrom struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+type ReferenceGrantTo struct {
+	// Name of the secret
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []KlusterReferenceGrant `json:"items,omitempty"`
+}
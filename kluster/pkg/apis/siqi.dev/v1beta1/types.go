@@ -0,0 +1,127 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ClusterID",type=string,JSONPath=`.status.klusterID`
+// +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+type Kluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Spec   KlusterSpec   `json:"spec"`
+	Status KlusterStatus `json:"status,omitempty"`
+}
+
+type KlusterSpec struct {
+	// Name of the cloud cluster
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="name is immutable"
+	Name string `json:"name,omitempty"`
+	// Region slug of the cloud cluster, like nyc1
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^[a-z]+[0-9]+$`
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="region is immutable"
+	Region  string `json:"region"`
+	Version string `json:"version,omitempty"`
+	// TokenSecretRef is the secret with the provider token
+	TokenSecretRef *SecretReference `json:"tokenSecretRef,omitempty"`
+	// Credentials is the name of the KlusterCredentials the token is read from instead of a token secret.
+	// Without both the controller uses its default token.
+	Credentials string `json:"credentials,omitempty"`
+	// Provider is the cloud provider the cluster is created in, defaults to digitalocean
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="provider is immutable"
+	Provider string `json:"provider,omitempty"`
+	// DriftPolicy decides what happens when the cloud cluster is changed outside of the kluster,
+	// Correct (the default) brings it back to the spec and Report only sets the Drifted condition
+	// +kubebuilder:validation:Enum=Correct;Report
+	DriftPolicy string `json:"driftPolicy,omitempty"`
+
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	NodePools []NodePool `json:"nodePools,omitempty"`
+}
+
+// SecretReference points to a secret, the namespace defaults to the namespace of the kluster
+type SecretReference struct {
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Key of the token in the secret, defaults to the -token-secret-key of the controller
+	Key string `json:"key,omitempty"`
+}
+
+type NodePool struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Size of the droplets, it can not be changed, add a node pool with the new size and remove the old one instead
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="size is immutable, replace the node pool to change it"
+	Size string `json:"size,omitempty"`
+	// Count is the number of nodes, or the initial number of nodes if the pool scales automatically
+	// +kubebuilder:validation:Minimum=1
+	Count int `json:"count,omitempty"`
+	// Autoscaling lets the provider resize the pool, it is off when empty
+	Autoscaling *NodePoolAutoscaling `json:"autoscaling,omitempty"`
+}
+
+// NodePoolAutoscaling is the range the provider resizes a node pool in
+// +kubebuilder:validation:XValidation:rule="self.maxNodes >= self.minNodes",message="maxNodes must not be less than minNodes"
+type NodePoolAutoscaling struct {
+	// +kubebuilder:validation:Minimum=0
+	MinNodes int `json:"minNodes"`
+	// +kubebuilder:validation:Minimum=1
+	MaxNodes int `json:"maxNodes"`
+}
+
+type KlusterStatus struct {
+	KlusterID string `json:"klusterID,omitempty"`
+	Progress  string `json:"progress,omitempty"`
+
+	// KubeConfigSecret is the name of the secret in the kluster's namespace that holds the
+	// kubeconfig of the cloud cluster under the "kubeconfig" key
+	KubeConfigSecret string `json:"kubeConfigSecret,omitempty"`
+	// KubeConfigExpiresAt is when the credentials in the kubeconfig expire, the controller refreshes them before
+	KubeConfigExpiresAt *metav1.Time `json:"kubeConfigExpiresAt,omitempty"`
+
+	// NodePools is the state of each node pool in the cloud
+	NodePools []NodePoolStatus `json:"nodePools,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that was last reconciled successfully
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastError is the error of the last failed reconcile, it is cleared by a successful one
+	LastError     string       `json:"lastError,omitempty"`
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+	// LastReconcileTime is when the kluster was last reconciled successfully
+	LastReconcileTime *metav1.Time `json:"lastReconcileTime,omitempty"`
+
+	// Conditions are the latest observations of the kluster's state
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type NodePoolStatus struct {
+	Name       string `json:"name,omitempty"`
+	ID         string `json:"id,omitempty"`
+	Size       string `json:"size,omitempty"`
+	Count      int    `json:"count,omitempty"`
+	ReadyNodes int    `json:"readyNodes,omitempty"`
+	State      string `json:"state,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type KlusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Kluster `json:"items,omitempty"`
+}
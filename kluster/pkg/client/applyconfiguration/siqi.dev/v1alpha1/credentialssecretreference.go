@@ -0,0 +1,57 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// CredentialsSecretReferenceApplyConfiguration represents an declarative configuration of the CredentialsSecretReference type for use
+// with apply.
+type CredentialsSecretReferenceApplyConfiguration struct {
+	Namespace *string `json:"namespace,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Key       *string `json:"key,omitempty"`
+}
+
+// CredentialsSecretReferenceApplyConfiguration constructs an declarative configuration of the CredentialsSecretReference type for use with
+// apply.
+func CredentialsSecretReference() *CredentialsSecretReferenceApplyConfiguration {
+	return &CredentialsSecretReferenceApplyConfiguration{}
+}
+
+// WithNamespace sets the Namespace field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Namespace field is set to the value of the last call.
+func (b *CredentialsSecretReferenceApplyConfiguration) WithNamespace(value string) *CredentialsSecretReferenceApplyConfiguration {
+	b.Namespace = &value
+	return b
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *CredentialsSecretReferenceApplyConfiguration) WithName(value string) *CredentialsSecretReferenceApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithKey sets the Key field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Key field is set to the value of the last call.
+func (b *CredentialsSecretReferenceApplyConfiguration) WithKey(value string) *CredentialsSecretReferenceApplyConfiguration {
+	b.Key = &value
+	return b
+}
@@ -18,12 +18,23 @@ limitations under the License.
 
 package v1alpha1
 
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 // KlsuterStatusApplyConfiguration represents an declarative configuration of the KlsuterStatus type for use
 // with apply.
 type KlsuterStatusApplyConfiguration struct {
-	KlusterID  *string `json:"klusterID,omitempty"`
-	Progress   *string `json:"progress,omitempty"`
-	KubeConfig *string `json:"kubeConfig,omitempty"`
+	KlusterID           *string                            `json:"klusterID,omitempty"`
+	Progress            *string                            `json:"progress,omitempty"`
+	KubeConfigSecret    *string                            `json:"kubeConfigSecret,omitempty"`
+	KubeConfigExpiresAt *v1.Time                           `json:"kubeConfigExpiresAt,omitempty"`
+	NodePools           []NodePoolStatusApplyConfiguration `json:"nodePools,omitempty"`
+	ObservedGeneration  *int64                             `json:"observedGeneration,omitempty"`
+	LastError           *string                            `json:"lastError,omitempty"`
+	LastErrorTime       *v1.Time                           `json:"lastErrorTime,omitempty"`
+	LastReconcileTime   *v1.Time                           `json:"lastReconcileTime,omitempty"`
+	Conditions          []v1.Condition                     `json:"conditions,omitempty"`
 }
 
 // KlsuterStatusApplyConfiguration constructs an declarative configuration of the KlsuterStatus type for use with
@@ -48,10 +59,73 @@ func (b *KlsuterStatusApplyConfiguration) WithProgress(value string) *KlsuterSta
 	return b
 }
 
-// WithKubeConfig sets the KubeConfig field in the declarative configuration to the given value
+// WithKubeConfigSecret sets the KubeConfigSecret field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KubeConfigSecret field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithKubeConfigSecret(value string) *KlsuterStatusApplyConfiguration {
+	b.KubeConfigSecret = &value
+	return b
+}
+
+// WithKubeConfigExpiresAt sets the KubeConfigExpiresAt field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KubeConfigExpiresAt field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithKubeConfigExpiresAt(value v1.Time) *KlsuterStatusApplyConfiguration {
+	b.KubeConfigExpiresAt = &value
+	return b
+}
+
+// WithNodePools adds the given value to the NodePools field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the NodePools field.
+func (b *KlsuterStatusApplyConfiguration) WithNodePools(values ...*NodePoolStatusApplyConfiguration) *KlsuterStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithNodePools")
+		}
+		b.NodePools = append(b.NodePools, *values[i])
+	}
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
-// If called multiple times, the KubeConfig field is set to the value of the last call.
-func (b *KlsuterStatusApplyConfiguration) WithKubeConfig(value string) *KlsuterStatusApplyConfiguration {
-	b.KubeConfig = &value
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithObservedGeneration(value int64) *KlsuterStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithLastError sets the LastError field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastError field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithLastError(value string) *KlsuterStatusApplyConfiguration {
+	b.LastError = &value
+	return b
+}
+
+// WithLastErrorTime sets the LastErrorTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastErrorTime field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithLastErrorTime(value v1.Time) *KlsuterStatusApplyConfiguration {
+	b.LastErrorTime = &value
+	return b
+}
+
+// WithLastReconcileTime sets the LastReconcileTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastReconcileTime field is set to the value of the last call.
+func (b *KlsuterStatusApplyConfiguration) WithLastReconcileTime(value v1.Time) *KlsuterStatusApplyConfiguration {
+	b.LastReconcileTime = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *KlsuterStatusApplyConfiguration) WithConditions(values ...v1.Condition) *KlsuterStatusApplyConfiguration {
+	for i := range values {
+		b.Conditions = append(b.Conditions, values[i])
+	}
 	return b
 }
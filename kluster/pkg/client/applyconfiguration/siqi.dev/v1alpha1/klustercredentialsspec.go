@@ -0,0 +1,68 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// KlusterCredentialsSpecApplyConfiguration represents an declarative configuration of the KlusterCredentialsSpec type for use
+// with apply.
+type KlusterCredentialsSpecApplyConfiguration struct {
+	SecretRef         *CredentialsSecretReferenceApplyConfiguration `json:"secretRef,omitempty"`
+	File              *string                                       `json:"file,omitempty"`
+	Env               *string                                       `json:"env,omitempty"`
+	AllowedNamespaces []string                                      `json:"allowedNamespaces,omitempty"`
+}
+
+// KlusterCredentialsSpecApplyConfiguration constructs an declarative configuration of the KlusterCredentialsSpec type for use with
+// apply.
+func KlusterCredentialsSpec() *KlusterCredentialsSpecApplyConfiguration {
+	return &KlusterCredentialsSpecApplyConfiguration{}
+}
+
+// WithSecretRef sets the SecretRef field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SecretRef field is set to the value of the last call.
+func (b *KlusterCredentialsSpecApplyConfiguration) WithSecretRef(value *CredentialsSecretReferenceApplyConfiguration) *KlusterCredentialsSpecApplyConfiguration {
+	b.SecretRef = value
+	return b
+}
+
+// WithFile sets the File field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the File field is set to the value of the last call.
+func (b *KlusterCredentialsSpecApplyConfiguration) WithFile(value string) *KlusterCredentialsSpecApplyConfiguration {
+	b.File = &value
+	return b
+}
+
+// WithEnv sets the Env field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Env field is set to the value of the last call.
+func (b *KlusterCredentialsSpecApplyConfiguration) WithEnv(value string) *KlusterCredentialsSpecApplyConfiguration {
+	b.Env = &value
+	return b
+}
+
+// WithAllowedNamespaces adds the given value to the AllowedNamespaces field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the AllowedNamespaces field.
+func (b *KlusterCredentialsSpecApplyConfiguration) WithAllowedNamespaces(values ...string) *KlusterCredentialsSpecApplyConfiguration {
+	for i := range values {
+		b.AllowedNamespaces = append(b.AllowedNamespaces, values[i])
+	}
+	return b
+}
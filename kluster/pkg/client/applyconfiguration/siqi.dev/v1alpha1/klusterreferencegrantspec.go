@@ -0,0 +1,58 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1alpha1
+
+// KlusterReferenceGrantSpecApplyConfiguration represents an declarative configuration of the KlusterReferenceGrantSpec type for use
+// with apply.
+type KlusterReferenceGrantSpecApplyConfiguration struct {
+	From []ReferenceGrantFromApplyConfiguration `json:"from,omitempty"`
+	To   []ReferenceGrantToApplyConfiguration   `json:"to,omitempty"`
+}
+
+// KlusterReferenceGrantSpecApplyConfiguration constructs an declarative configuration of the KlusterReferenceGrantSpec type for use with
+// apply.
+func KlusterReferenceGrantSpec() *KlusterReferenceGrantSpecApplyConfiguration {
+	return &KlusterReferenceGrantSpecApplyConfiguration{}
+}
+
+// WithFrom adds the given value to the From field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the From field.
+func (b *KlusterReferenceGrantSpecApplyConfiguration) WithFrom(values ...*ReferenceGrantFromApplyConfiguration) *KlusterReferenceGrantSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithFrom")
+		}
+		b.From = append(b.From, *values[i])
+	}
+	return b
+}
+
+// WithTo adds the given value to the To field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the To field.
+func (b *KlusterReferenceGrantSpecApplyConfiguration) WithTo(values ...*ReferenceGrantToApplyConfiguration) *KlusterReferenceGrantSpecApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithTo")
+		}
+		b.To = append(b.To, *values[i])
+	}
+	return b
+}
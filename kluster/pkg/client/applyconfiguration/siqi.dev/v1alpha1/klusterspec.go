@@ -21,11 +21,15 @@ package v1alpha1
 // KlusterSpecApplyConfiguration represents an declarative configuration of the KlusterSpec type for use
 // with apply.
 type KlusterSpecApplyConfiguration struct {
-	Name        *string                      `json:"name,omitempty"`
-	Region      *string                      `json:"region,omitempty"`
-	Version     *string                      `json:"version,omitempty"`
-	TokenSecret *string                      `json:"tokenSecret,omitempty"`
-	NodePools   []NodePoolApplyConfiguration `json:"nodePools,omitempty"`
+	Name           *string                      `json:"name,omitempty"`
+	Region         *string                      `json:"region,omitempty"`
+	Version        *string                      `json:"version,omitempty"`
+	TokenSecret    *string                      `json:"tokenSecret,omitempty"`
+	TokenSecretKey *string                      `json:"tokenSecretKey,omitempty"`
+	Credentials    *string                      `json:"credentials,omitempty"`
+	Provider       *string                      `json:"provider,omitempty"`
+	DriftPolicy    *string                      `json:"driftPolicy,omitempty"`
+	NodePools      []NodePoolApplyConfiguration `json:"nodePools,omitempty"`
 }
 
 // KlusterSpecApplyConfiguration constructs an declarative configuration of the KlusterSpec type for use with
@@ -66,6 +70,38 @@ func (b *KlusterSpecApplyConfiguration) WithTokenSecret(value string) *KlusterSp
 	return b
 }
 
+// WithTokenSecretKey sets the TokenSecretKey field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the TokenSecretKey field is set to the value of the last call.
+func (b *KlusterSpecApplyConfiguration) WithTokenSecretKey(value string) *KlusterSpecApplyConfiguration {
+	b.TokenSecretKey = &value
+	return b
+}
+
+// WithCredentials sets the Credentials field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Credentials field is set to the value of the last call.
+func (b *KlusterSpecApplyConfiguration) WithCredentials(value string) *KlusterSpecApplyConfiguration {
+	b.Credentials = &value
+	return b
+}
+
+// WithProvider sets the Provider field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Provider field is set to the value of the last call.
+func (b *KlusterSpecApplyConfiguration) WithProvider(value string) *KlusterSpecApplyConfiguration {
+	b.Provider = &value
+	return b
+}
+
+// WithDriftPolicy sets the DriftPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DriftPolicy field is set to the value of the last call.
+func (b *KlusterSpecApplyConfiguration) WithDriftPolicy(value string) *KlusterSpecApplyConfiguration {
+	b.DriftPolicy = &value
+	return b
+}
+
 // WithNodePools adds the given value to the NodePools field in the declarative configuration
 // and returns the receiver, so that objects can be build by chaining "With" function invocations.
 // If called multiple times, values provided by each call will be appended to the NodePools field.
@@ -21,9 +21,12 @@ package v1alpha1
 // NodePoolApplyConfiguration represents an declarative configuration of the NodePool type for use
 // with apply.
 type NodePoolApplyConfiguration struct {
-	Size  *string `json:"size,omitempty"`
-	Name  *string `json:"name,omitempty"`
-	Count *int    `json:"count,omitempty"`
+	Size      *string `json:"size,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	Count     *int    `json:"count,omitempty"`
+	AutoScale *bool   `json:"autoScale,omitempty"`
+	MinNodes  *int    `json:"minNodes,omitempty"`
+	MaxNodes  *int    `json:"maxNodes,omitempty"`
 }
 
 // NodePoolApplyConfiguration constructs an declarative configuration of the NodePool type for use with
@@ -55,3 +58,27 @@ func (b *NodePoolApplyConfiguration) WithCount(value int) *NodePoolApplyConfigur
 	b.Count = &value
 	return b
 }
+
+// WithAutoScale sets the AutoScale field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutoScale field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithAutoScale(value bool) *NodePoolApplyConfiguration {
+	b.AutoScale = &value
+	return b
+}
+
+// WithMinNodes sets the MinNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MinNodes field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithMinNodes(value int) *NodePoolApplyConfiguration {
+	b.MinNodes = &value
+	return b
+}
+
+// WithMaxNodes sets the MaxNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxNodes field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithMaxNodes(value int) *NodePoolApplyConfiguration {
+	b.MaxNodes = &value
+	return b
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KlusterStatusApplyConfiguration represents an declarative configuration of the KlusterStatus type for use
+// with apply.
+type KlusterStatusApplyConfiguration struct {
+	KlusterID           *string                            `json:"klusterID,omitempty"`
+	Progress            *string                            `json:"progress,omitempty"`
+	KubeConfigSecret    *string                            `json:"kubeConfigSecret,omitempty"`
+	KubeConfigExpiresAt *v1.Time                           `json:"kubeConfigExpiresAt,omitempty"`
+	NodePools           []NodePoolStatusApplyConfiguration `json:"nodePools,omitempty"`
+	ObservedGeneration  *int64                             `json:"observedGeneration,omitempty"`
+	LastError           *string                            `json:"lastError,omitempty"`
+	LastErrorTime       *v1.Time                           `json:"lastErrorTime,omitempty"`
+	LastReconcileTime   *v1.Time                           `json:"lastReconcileTime,omitempty"`
+	Conditions          []v1.Condition                     `json:"conditions,omitempty"`
+}
+
+// KlusterStatusApplyConfiguration constructs an declarative configuration of the KlusterStatus type for use with
+// apply.
+func KlusterStatus() *KlusterStatusApplyConfiguration {
+	return &KlusterStatusApplyConfiguration{}
+}
+
+// WithKlusterID sets the KlusterID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KlusterID field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithKlusterID(value string) *KlusterStatusApplyConfiguration {
+	b.KlusterID = &value
+	return b
+}
+
+// WithProgress sets the Progress field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Progress field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithProgress(value string) *KlusterStatusApplyConfiguration {
+	b.Progress = &value
+	return b
+}
+
+// WithKubeConfigSecret sets the KubeConfigSecret field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KubeConfigSecret field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithKubeConfigSecret(value string) *KlusterStatusApplyConfiguration {
+	b.KubeConfigSecret = &value
+	return b
+}
+
+// WithKubeConfigExpiresAt sets the KubeConfigExpiresAt field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the KubeConfigExpiresAt field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithKubeConfigExpiresAt(value v1.Time) *KlusterStatusApplyConfiguration {
+	b.KubeConfigExpiresAt = &value
+	return b
+}
+
+// WithNodePools adds the given value to the NodePools field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the NodePools field.
+func (b *KlusterStatusApplyConfiguration) WithNodePools(values ...*NodePoolStatusApplyConfiguration) *KlusterStatusApplyConfiguration {
+	for i := range values {
+		if values[i] == nil {
+			panic("nil value passed to WithNodePools")
+		}
+		b.NodePools = append(b.NodePools, *values[i])
+	}
+	return b
+}
+
+// WithObservedGeneration sets the ObservedGeneration field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ObservedGeneration field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithObservedGeneration(value int64) *KlusterStatusApplyConfiguration {
+	b.ObservedGeneration = &value
+	return b
+}
+
+// WithLastError sets the LastError field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastError field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithLastError(value string) *KlusterStatusApplyConfiguration {
+	b.LastError = &value
+	return b
+}
+
+// WithLastErrorTime sets the LastErrorTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastErrorTime field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithLastErrorTime(value v1.Time) *KlusterStatusApplyConfiguration {
+	b.LastErrorTime = &value
+	return b
+}
+
+// WithLastReconcileTime sets the LastReconcileTime field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastReconcileTime field is set to the value of the last call.
+func (b *KlusterStatusApplyConfiguration) WithLastReconcileTime(value v1.Time) *KlusterStatusApplyConfiguration {
+	b.LastReconcileTime = &value
+	return b
+}
+
+// WithConditions adds the given value to the Conditions field in the declarative configuration
+// and returns the receiver, so that objects can be build by chaining "With" function invocations.
+// If called multiple times, values provided by each call will be appended to the Conditions field.
+func (b *KlusterStatusApplyConfiguration) WithConditions(values ...v1.Condition) *KlusterStatusApplyConfiguration {
+	for i := range values {
+		b.Conditions = append(b.Conditions, values[i])
+	}
+	return b
+}
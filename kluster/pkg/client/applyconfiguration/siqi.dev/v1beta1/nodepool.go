@@ -0,0 +1,66 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// NodePoolApplyConfiguration represents an declarative configuration of the NodePool type for use
+// with apply.
+type NodePoolApplyConfiguration struct {
+	Name        *string                                `json:"name,omitempty"`
+	Size        *string                                `json:"size,omitempty"`
+	Count       *int                                   `json:"count,omitempty"`
+	Autoscaling *NodePoolAutoscalingApplyConfiguration `json:"autoscaling,omitempty"`
+}
+
+// NodePoolApplyConfiguration constructs an declarative configuration of the NodePool type for use with
+// apply.
+func NodePool() *NodePoolApplyConfiguration {
+	return &NodePoolApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithName(value string) *NodePoolApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithSize sets the Size field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Size field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithSize(value string) *NodePoolApplyConfiguration {
+	b.Size = &value
+	return b
+}
+
+// WithCount sets the Count field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Count field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithCount(value int) *NodePoolApplyConfiguration {
+	b.Count = &value
+	return b
+}
+
+// WithAutoscaling sets the Autoscaling field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Autoscaling field is set to the value of the last call.
+func (b *NodePoolApplyConfiguration) WithAutoscaling(value *NodePoolAutoscalingApplyConfiguration) *NodePoolApplyConfiguration {
+	b.Autoscaling = value
+	return b
+}
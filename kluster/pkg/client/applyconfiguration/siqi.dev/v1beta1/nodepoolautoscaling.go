@@ -0,0 +1,48 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// NodePoolAutoscalingApplyConfiguration represents an declarative configuration of the NodePoolAutoscaling type for use
+// with apply.
+type NodePoolAutoscalingApplyConfiguration struct {
+	MinNodes *int `json:"minNodes,omitempty"`
+	MaxNodes *int `json:"maxNodes,omitempty"`
+}
+
+// NodePoolAutoscalingApplyConfiguration constructs an declarative configuration of the NodePoolAutoscaling type for use with
+// apply.
+func NodePoolAutoscaling() *NodePoolAutoscalingApplyConfiguration {
+	return &NodePoolAutoscalingApplyConfiguration{}
+}
+
+// WithMinNodes sets the MinNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MinNodes field is set to the value of the last call.
+func (b *NodePoolAutoscalingApplyConfiguration) WithMinNodes(value int) *NodePoolAutoscalingApplyConfiguration {
+	b.MinNodes = &value
+	return b
+}
+
+// WithMaxNodes sets the MaxNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxNodes field is set to the value of the last call.
+func (b *NodePoolAutoscalingApplyConfiguration) WithMaxNodes(value int) *NodePoolAutoscalingApplyConfiguration {
+	b.MaxNodes = &value
+	return b
+}
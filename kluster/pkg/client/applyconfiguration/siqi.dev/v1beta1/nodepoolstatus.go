@@ -0,0 +1,84 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta1
+
+// NodePoolStatusApplyConfiguration represents an declarative configuration of the NodePoolStatus type for use
+// with apply.
+type NodePoolStatusApplyConfiguration struct {
+	Name       *string `json:"name,omitempty"`
+	ID         *string `json:"id,omitempty"`
+	Size       *string `json:"size,omitempty"`
+	Count      *int    `json:"count,omitempty"`
+	ReadyNodes *int    `json:"readyNodes,omitempty"`
+	State      *string `json:"state,omitempty"`
+}
+
+// NodePoolStatusApplyConfiguration constructs an declarative configuration of the NodePoolStatus type for use with
+// apply.
+func NodePoolStatus() *NodePoolStatusApplyConfiguration {
+	return &NodePoolStatusApplyConfiguration{}
+}
+
+// WithName sets the Name field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Name field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithName(value string) *NodePoolStatusApplyConfiguration {
+	b.Name = &value
+	return b
+}
+
+// WithID sets the ID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ID field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithID(value string) *NodePoolStatusApplyConfiguration {
+	b.ID = &value
+	return b
+}
+
+// WithSize sets the Size field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Size field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithSize(value string) *NodePoolStatusApplyConfiguration {
+	b.Size = &value
+	return b
+}
+
+// WithCount sets the Count field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Count field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithCount(value int) *NodePoolStatusApplyConfiguration {
+	b.Count = &value
+	return b
+}
+
+// WithReadyNodes sets the ReadyNodes field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReadyNodes field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithReadyNodes(value int) *NodePoolStatusApplyConfiguration {
+	b.ReadyNodes = &value
+	return b
+}
+
+// WithState sets the State field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the State field is set to the value of the last call.
+func (b *NodePoolStatusApplyConfiguration) WithState(value string) *NodePoolStatusApplyConfiguration {
+	b.State = &value
+	return b
+}
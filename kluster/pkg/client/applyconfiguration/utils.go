@@ -20,7 +20,9 @@ package applyconfiguration
 
 import (
 	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	v1beta1 "kluster/pkg/apis/siqi.dev/v1beta1"
 	siqidevv1alpha1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1alpha1"
+	siqidevv1beta1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1beta1"
 
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 )
@@ -30,14 +32,46 @@ import (
 func ForKind(kind schema.GroupVersionKind) interface{} {
 	switch kind {
 	// Group=siqi.dev, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithKind("CredentialsSecretReference"):
+		return &siqidevv1alpha1.CredentialsSecretReferenceApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KlsuterStatus"):
 		return &siqidevv1alpha1.KlsuterStatusApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("Kluster"):
 		return &siqidevv1alpha1.KlusterApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KlusterCredentials"):
+		return &siqidevv1alpha1.KlusterCredentialsApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KlusterCredentialsSpec"):
+		return &siqidevv1alpha1.KlusterCredentialsSpecApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KlusterReferenceGrant"):
+		return &siqidevv1alpha1.KlusterReferenceGrantApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("KlusterReferenceGrantSpec"):
+		return &siqidevv1alpha1.KlusterReferenceGrantSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("KlusterSpec"):
 		return &siqidevv1alpha1.KlusterSpecApplyConfiguration{}
 	case v1alpha1.SchemeGroupVersion.WithKind("NodePool"):
 		return &siqidevv1alpha1.NodePoolApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("NodePoolStatus"):
+		return &siqidevv1alpha1.NodePoolStatusApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ReferenceGrantFrom"):
+		return &siqidevv1alpha1.ReferenceGrantFromApplyConfiguration{}
+	case v1alpha1.SchemeGroupVersion.WithKind("ReferenceGrantTo"):
+		return &siqidevv1alpha1.ReferenceGrantToApplyConfiguration{}
+
+		// Group=siqi.dev, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithKind("Kluster"):
+		return &siqidevv1beta1.KlusterApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("KlusterSpec"):
+		return &siqidevv1beta1.KlusterSpecApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("KlusterStatus"):
+		return &siqidevv1beta1.KlusterStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("NodePool"):
+		return &siqidevv1beta1.NodePoolApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("NodePoolAutoscaling"):
+		return &siqidevv1beta1.NodePoolAutoscalingApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("NodePoolStatus"):
+		return &siqidevv1beta1.NodePoolStatusApplyConfiguration{}
+	case v1beta1.SchemeGroupVersion.WithKind("SecretReference"):
+		return &siqidevv1beta1.SecretReferenceApplyConfiguration{}
 
 	}
 	return nil
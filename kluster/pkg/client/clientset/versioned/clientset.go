@@ -21,6 +21,7 @@ package versioned
 import (
 	"fmt"
 	siqiv1alpha1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1alpha1"
+	siqiv1beta1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1beta1"
 	"net/http"
 
 	discovery "k8s.io/client-go/discovery"
@@ -31,12 +32,14 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	SiqiV1alpha1() siqiv1alpha1.SiqiV1alpha1Interface
+	SiqiV1beta1() siqiv1beta1.SiqiV1beta1Interface
 }
 
 // Clientset contains the clients for groups.
 type Clientset struct {
 	*discovery.DiscoveryClient
 	siqiV1alpha1 *siqiv1alpha1.SiqiV1alpha1Client
+	siqiV1beta1  *siqiv1beta1.SiqiV1beta1Client
 }
 
 // SiqiV1alpha1 retrieves the SiqiV1alpha1Client
@@ -44,6 +47,11 @@ func (c *Clientset) SiqiV1alpha1() siqiv1alpha1.SiqiV1alpha1Interface {
 	return c.siqiV1alpha1
 }
 
+// SiqiV1beta1 retrieves the SiqiV1beta1Client
+func (c *Clientset) SiqiV1beta1() siqiv1beta1.SiqiV1beta1Interface {
+	return c.siqiV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -92,6 +100,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.siqiV1beta1, err = siqiv1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -114,6 +126,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.siqiV1alpha1 = siqiv1alpha1.New(c)
+	cs.siqiV1beta1 = siqiv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
@@ -22,6 +22,8 @@ import (
 	clientset "kluster/pkg/client/clientset/versioned"
 	siqiv1alpha1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1alpha1"
 	fakesiqiv1alpha1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1alpha1/fake"
+	siqiv1beta1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1beta1"
+	fakesiqiv1beta1 "kluster/pkg/client/clientset/versioned/typed/siqi.dev/v1beta1/fake"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
@@ -84,3 +86,8 @@ var (
 func (c *Clientset) SiqiV1alpha1() siqiv1alpha1.SiqiV1alpha1Interface {
 	return &fakesiqiv1alpha1.FakeSiqiV1alpha1{Fake: &c.Fake}
 }
+
+// SiqiV1beta1 retrieves the SiqiV1beta1Client
+func (c *Clientset) SiqiV1beta1() siqiv1beta1.SiqiV1beta1Interface {
+	return &fakesiqiv1beta1.FakeSiqiV1beta1{Fake: &c.Fake}
+}
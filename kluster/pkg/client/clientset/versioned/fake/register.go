@@ -20,6 +20,7 @@ package fake
 
 import (
 	siqiv1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	siqiv1beta1 "kluster/pkg/apis/siqi.dev/v1beta1"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -33,6 +34,7 @@ var codecs = serializer.NewCodecFactory(scheme)
 
 var localSchemeBuilder = runtime.SchemeBuilder{
 	siqiv1alpha1.AddToScheme,
+	siqiv1beta1.AddToScheme,
 }
 
 // AddToScheme adds all types of this clientset into the given scheme. This allows composition
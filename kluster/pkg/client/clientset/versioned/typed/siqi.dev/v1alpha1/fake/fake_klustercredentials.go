@@ -0,0 +1,145 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	siqidevv1alpha1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1alpha1"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterCredentials implements KlusterCredentialsInterface
+type FakeKlusterCredentials struct {
+	Fake *FakeSiqiV1alpha1
+}
+
+var klustercredentialsResource = v1alpha1.SchemeGroupVersion.WithResource("klustercredentials")
+
+var klustercredentialsKind = v1alpha1.SchemeGroupVersion.WithKind("KlusterCredentials")
+
+// Get takes name of the klusterCredentials, and returns the corresponding klusterCredentials object, and an error if there is any.
+func (c *FakeKlusterCredentials) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(klustercredentialsResource, name), &v1alpha1.KlusterCredentials{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterCredentials), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterCredentials that match those selectors.
+func (c *FakeKlusterCredentials) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterCredentialsList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(klustercredentialsResource, klustercredentialsKind, opts), &v1alpha1.KlusterCredentialsList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterCredentialsList{ListMeta: obj.(*v1alpha1.KlusterCredentialsList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterCredentialsList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterCredentials.
+func (c *FakeKlusterCredentials) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(klustercredentialsResource, opts))
+}
+
+// Create takes the representation of a klusterCredentials and creates it.  Returns the server's representation of the klusterCredentials, and an error, if there is any.
+func (c *FakeKlusterCredentials) Create(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.CreateOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(klustercredentialsResource, klusterCredentials), &v1alpha1.KlusterCredentials{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterCredentials), err
+}
+
+// Update takes the representation of a klusterCredentials and updates it. Returns the server's representation of the klusterCredentials, and an error, if there is any.
+func (c *FakeKlusterCredentials) Update(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.UpdateOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(klustercredentialsResource, klusterCredentials), &v1alpha1.KlusterCredentials{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterCredentials), err
+}
+
+// Delete takes name of the klusterCredentials and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterCredentials) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteActionWithOptions(klustercredentialsResource, name, opts), &v1alpha1.KlusterCredentials{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterCredentials) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(klustercredentialsResource, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterCredentialsList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterCredentials.
+func (c *FakeKlusterCredentials) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterCredentials, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(klustercredentialsResource, name, pt, data, subresources...), &v1alpha1.KlusterCredentials{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterCredentials), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied klusterCredentials.
+func (c *FakeKlusterCredentials) Apply(ctx context.Context, klusterCredentials *siqidevv1alpha1.KlusterCredentialsApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	if klusterCredentials == nil {
+		return nil, fmt.Errorf("klusterCredentials provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(klusterCredentials)
+	if err != nil {
+		return nil, err
+	}
+	name := klusterCredentials.Name
+	if name == nil {
+		return nil, fmt.Errorf("klusterCredentials.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(klustercredentialsResource, *name, types.ApplyPatchType, data), &v1alpha1.KlusterCredentials{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterCredentials), err
+}
@@ -0,0 +1,154 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	siqidevv1alpha1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1alpha1"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeKlusterReferenceGrants implements KlusterReferenceGrantInterface
+type FakeKlusterReferenceGrants struct {
+	Fake *FakeSiqiV1alpha1
+	ns   string
+}
+
+var klusterreferencegrantsResource = v1alpha1.SchemeGroupVersion.WithResource("klusterreferencegrants")
+
+var klusterreferencegrantsKind = v1alpha1.SchemeGroupVersion.WithKind("KlusterReferenceGrant")
+
+// Get takes name of the klusterReferenceGrant, and returns the corresponding klusterReferenceGrant object, and an error if there is any.
+func (c *FakeKlusterReferenceGrants) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(klusterreferencegrantsResource, c.ns, name), &v1alpha1.KlusterReferenceGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), err
+}
+
+// List takes label and field selectors, and returns the list of KlusterReferenceGrants that match those selectors.
+func (c *FakeKlusterReferenceGrants) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterReferenceGrantList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(klusterreferencegrantsResource, klusterreferencegrantsKind, c.ns, opts), &v1alpha1.KlusterReferenceGrantList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.KlusterReferenceGrantList{ListMeta: obj.(*v1alpha1.KlusterReferenceGrantList).ListMeta}
+	for _, item := range obj.(*v1alpha1.KlusterReferenceGrantList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested klusterReferenceGrants.
+func (c *FakeKlusterReferenceGrants) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(klusterreferencegrantsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a klusterReferenceGrant and creates it.  Returns the server's representation of the klusterReferenceGrant, and an error, if there is any.
+func (c *FakeKlusterReferenceGrants) Create(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.CreateOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(klusterreferencegrantsResource, c.ns, klusterReferenceGrant), &v1alpha1.KlusterReferenceGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), err
+}
+
+// Update takes the representation of a klusterReferenceGrant and updates it. Returns the server's representation of the klusterReferenceGrant, and an error, if there is any.
+func (c *FakeKlusterReferenceGrants) Update(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.UpdateOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(klusterreferencegrantsResource, c.ns, klusterReferenceGrant), &v1alpha1.KlusterReferenceGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), err
+}
+
+// Delete takes name of the klusterReferenceGrant and deletes it. Returns an error if one occurs.
+func (c *FakeKlusterReferenceGrants) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(klusterreferencegrantsResource, c.ns, name, opts), &v1alpha1.KlusterReferenceGrant{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeKlusterReferenceGrants) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(klusterreferencegrantsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.KlusterReferenceGrantList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched klusterReferenceGrant.
+func (c *FakeKlusterReferenceGrants) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klusterreferencegrantsResource, c.ns, name, pt, data, subresources...), &v1alpha1.KlusterReferenceGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), err
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied klusterReferenceGrant.
+func (c *FakeKlusterReferenceGrants) Apply(ctx context.Context, klusterReferenceGrant *siqidevv1alpha1.KlusterReferenceGrantApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	if klusterReferenceGrant == nil {
+		return nil, fmt.Errorf("klusterReferenceGrant provided to Apply must not be nil")
+	}
+	data, err := json.Marshal(klusterReferenceGrant)
+	if err != nil {
+		return nil, err
+	}
+	name := klusterReferenceGrant.Name
+	if name == nil {
+		return nil, fmt.Errorf("klusterReferenceGrant.Name must be provided to Apply")
+	}
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(klusterreferencegrantsResource, c.ns, *name, types.ApplyPatchType, data), &v1alpha1.KlusterReferenceGrant{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), err
+}
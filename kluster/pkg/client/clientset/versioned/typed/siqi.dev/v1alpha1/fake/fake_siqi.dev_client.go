@@ -33,6 +33,14 @@ func (c *FakeSiqiV1alpha1) Klusters(namespace string) v1alpha1.KlusterInterface
 	return &FakeKlusters{c, namespace}
 }
 
+func (c *FakeSiqiV1alpha1) KlusterCredentials() v1alpha1.KlusterCredentialsInterface {
+	return &FakeKlusterCredentials{c}
+}
+
+func (c *FakeSiqiV1alpha1) KlusterReferenceGrants(namespace string) v1alpha1.KlusterReferenceGrantInterface {
+	return &FakeKlusterReferenceGrants{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeSiqiV1alpha1) RESTClient() rest.Interface {
@@ -19,3 +19,7 @@ limitations under the License.
 package v1alpha1
 
 type KlusterExpansion interface{}
+
+type KlusterCredentialsExpansion interface{}
+
+type KlusterReferenceGrantExpansion interface{}
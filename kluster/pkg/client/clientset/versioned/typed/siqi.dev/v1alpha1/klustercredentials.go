@@ -0,0 +1,197 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	siqidevv1alpha1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1alpha1"
+	scheme "kluster/pkg/client/clientset/versioned/scheme"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// KlusterCredentialsGetter has a method to return a KlusterCredentialsInterface.
+// A group's client should implement this interface.
+type KlusterCredentialsGetter interface {
+	KlusterCredentials() KlusterCredentialsInterface
+}
+
+// KlusterCredentialsInterface has methods to work with KlusterCredentials resources.
+type KlusterCredentialsInterface interface {
+	Create(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.CreateOptions) (*v1alpha1.KlusterCredentials, error)
+	Update(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.UpdateOptions) (*v1alpha1.KlusterCredentials, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KlusterCredentials, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KlusterCredentialsList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterCredentials, err error)
+	Apply(ctx context.Context, klusterCredentials *siqidevv1alpha1.KlusterCredentialsApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterCredentials, err error)
+	KlusterCredentialsExpansion
+}
+
+// klusterCredentials implements KlusterCredentialsInterface
+type klusterCredentials struct {
+	client rest.Interface
+}
+
+// newKlusterCredentials returns a KlusterCredentials
+func newKlusterCredentials(c *SiqiV1alpha1Client) *klusterCredentials {
+	return &klusterCredentials{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the klusterCredentials, and returns the corresponding klusterCredentials object, and an error if there is any.
+func (c *klusterCredentials) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	result = &v1alpha1.KlusterCredentials{}
+	err = c.client.Get().
+		Resource("klustercredentials").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of KlusterCredentials that match those selectors.
+func (c *klusterCredentials) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterCredentialsList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.KlusterCredentialsList{}
+	err = c.client.Get().
+		Resource("klustercredentials").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested klusterCredentials.
+func (c *klusterCredentials) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("klustercredentials").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a klusterCredentials and creates it.  Returns the server's representation of the klusterCredentials, and an error, if there is any.
+func (c *klusterCredentials) Create(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.CreateOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	result = &v1alpha1.KlusterCredentials{}
+	err = c.client.Post().
+		Resource("klustercredentials").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterCredentials).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a klusterCredentials and updates it. Returns the server's representation of the klusterCredentials, and an error, if there is any.
+func (c *klusterCredentials) Update(ctx context.Context, klusterCredentials *v1alpha1.KlusterCredentials, opts v1.UpdateOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	result = &v1alpha1.KlusterCredentials{}
+	err = c.client.Put().
+		Resource("klustercredentials").
+		Name(klusterCredentials.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterCredentials).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the klusterCredentials and deletes it. Returns an error if one occurs.
+func (c *klusterCredentials) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("klustercredentials").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *klusterCredentials) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("klustercredentials").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched klusterCredentials.
+func (c *klusterCredentials) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterCredentials, err error) {
+	result = &v1alpha1.KlusterCredentials{}
+	err = c.client.Patch(pt).
+		Resource("klustercredentials").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied klusterCredentials.
+func (c *klusterCredentials) Apply(ctx context.Context, klusterCredentials *siqidevv1alpha1.KlusterCredentialsApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterCredentials, err error) {
+	if klusterCredentials == nil {
+		return nil, fmt.Errorf("klusterCredentials provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(klusterCredentials)
+	if err != nil {
+		return nil, err
+	}
+	name := klusterCredentials.Name
+	if name == nil {
+		return nil, fmt.Errorf("klusterCredentials.Name must be provided to Apply")
+	}
+	result = &v1alpha1.KlusterCredentials{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Resource("klustercredentials").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,208 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	json "encoding/json"
+	"fmt"
+	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	siqidevv1alpha1 "kluster/pkg/client/applyconfiguration/siqi.dev/v1alpha1"
+	scheme "kluster/pkg/client/clientset/versioned/scheme"
+	"time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// KlusterReferenceGrantsGetter has a method to return a KlusterReferenceGrantInterface.
+// A group's client should implement this interface.
+type KlusterReferenceGrantsGetter interface {
+	KlusterReferenceGrants(namespace string) KlusterReferenceGrantInterface
+}
+
+// KlusterReferenceGrantInterface has methods to work with KlusterReferenceGrant resources.
+type KlusterReferenceGrantInterface interface {
+	Create(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.CreateOptions) (*v1alpha1.KlusterReferenceGrant, error)
+	Update(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.UpdateOptions) (*v1alpha1.KlusterReferenceGrant, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.KlusterReferenceGrant, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.KlusterReferenceGrantList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterReferenceGrant, err error)
+	Apply(ctx context.Context, klusterReferenceGrant *siqidevv1alpha1.KlusterReferenceGrantApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterReferenceGrant, err error)
+	KlusterReferenceGrantExpansion
+}
+
+// klusterReferenceGrants implements KlusterReferenceGrantInterface
+type klusterReferenceGrants struct {
+	client rest.Interface
+	ns     string
+}
+
+// newKlusterReferenceGrants returns a KlusterReferenceGrants
+func newKlusterReferenceGrants(c *SiqiV1alpha1Client, namespace string) *klusterReferenceGrants {
+	return &klusterReferenceGrants{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the klusterReferenceGrant, and returns the corresponding klusterReferenceGrant object, and an error if there is any.
+func (c *klusterReferenceGrants) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	result = &v1alpha1.KlusterReferenceGrant{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of KlusterReferenceGrants that match those selectors.
+func (c *klusterReferenceGrants) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.KlusterReferenceGrantList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.KlusterReferenceGrantList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested klusterReferenceGrants.
+func (c *klusterReferenceGrants) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a klusterReferenceGrant and creates it.  Returns the server's representation of the klusterReferenceGrant, and an error, if there is any.
+func (c *klusterReferenceGrants) Create(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.CreateOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	result = &v1alpha1.KlusterReferenceGrant{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterReferenceGrant).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a klusterReferenceGrant and updates it. Returns the server's representation of the klusterReferenceGrant, and an error, if there is any.
+func (c *klusterReferenceGrants) Update(ctx context.Context, klusterReferenceGrant *v1alpha1.KlusterReferenceGrant, opts v1.UpdateOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	result = &v1alpha1.KlusterReferenceGrant{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		Name(klusterReferenceGrant.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(klusterReferenceGrant).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the klusterReferenceGrant and deletes it. Returns an error if one occurs.
+func (c *klusterReferenceGrants) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *klusterReferenceGrants) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched klusterReferenceGrant.
+func (c *klusterReferenceGrants) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	result = &v1alpha1.KlusterReferenceGrant{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Apply takes the given apply declarative configuration, applies it and returns the applied klusterReferenceGrant.
+func (c *klusterReferenceGrants) Apply(ctx context.Context, klusterReferenceGrant *siqidevv1alpha1.KlusterReferenceGrantApplyConfiguration, opts v1.ApplyOptions) (result *v1alpha1.KlusterReferenceGrant, err error) {
+	if klusterReferenceGrant == nil {
+		return nil, fmt.Errorf("klusterReferenceGrant provided to Apply must not be nil")
+	}
+	patchOpts := opts.ToPatchOptions()
+	data, err := json.Marshal(klusterReferenceGrant)
+	if err != nil {
+		return nil, err
+	}
+	name := klusterReferenceGrant.Name
+	if name == nil {
+		return nil, fmt.Errorf("klusterReferenceGrant.Name must be provided to Apply")
+	}
+	result = &v1alpha1.KlusterReferenceGrant{}
+	err = c.client.Patch(types.ApplyPatchType).
+		Namespace(c.ns).
+		Resource("klusterreferencegrants").
+		Name(*name).
+		VersionedParams(&patchOpts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
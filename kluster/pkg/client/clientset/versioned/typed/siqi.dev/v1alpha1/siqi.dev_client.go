@@ -29,6 +29,8 @@ import (
 type SiqiV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	KlustersGetter
+	KlusterCredentialsGetter
+	KlusterReferenceGrantsGetter
 }
 
 // SiqiV1alpha1Client is used to interact with features provided by the siqi.dev group.
@@ -40,6 +42,14 @@ func (c *SiqiV1alpha1Client) Klusters(namespace string) KlusterInterface {
 	return newKlusters(c, namespace)
 }
 
+func (c *SiqiV1alpha1Client) KlusterCredentials() KlusterCredentialsInterface {
+	return newKlusterCredentials(c)
+}
+
+func (c *SiqiV1alpha1Client) KlusterReferenceGrants(namespace string) KlusterReferenceGrantInterface {
+	return newKlusterReferenceGrants(c, namespace)
+}
+
 // NewForConfig creates a new SiqiV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -21,6 +21,7 @@ package externalversions
 import (
 	"fmt"
 	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	v1beta1 "kluster/pkg/apis/siqi.dev/v1beta1"
 
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
@@ -55,6 +56,14 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=siqi.dev, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("klusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Siqi().V1alpha1().Klusters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klustercredentials"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Siqi().V1alpha1().KlusterCredentials().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("klusterreferencegrants"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Siqi().V1alpha1().KlusterReferenceGrants().Informer()}, nil
+
+		// Group=siqi.dev, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("klusters"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Siqi().V1beta1().Klusters().Informer()}, nil
 
 	}
 
@@ -26,6 +26,10 @@ import (
 type Interface interface {
 	// Klusters returns a KlusterInformer.
 	Klusters() KlusterInformer
+	// KlusterCredentials returns a KlusterCredentialsInformer.
+	KlusterCredentials() KlusterCredentialsInformer
+	// KlusterReferenceGrants returns a KlusterReferenceGrantInformer.
+	KlusterReferenceGrants() KlusterReferenceGrantInformer
 }
 
 type version struct {
@@ -43,3 +47,13 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Klusters() KlusterInformer {
 	return &klusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// KlusterCredentials returns a KlusterCredentialsInformer.
+func (v *version) KlusterCredentials() KlusterCredentialsInformer {
+	return &klusterCredentialsInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// KlusterReferenceGrants returns a KlusterReferenceGrantInformer.
+func (v *version) KlusterReferenceGrants() KlusterReferenceGrantInformer {
+	return &klusterReferenceGrantInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
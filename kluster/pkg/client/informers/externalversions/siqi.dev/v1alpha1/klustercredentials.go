@@ -0,0 +1,89 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by informer-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	siqidevv1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+	versioned "kluster/pkg/client/clientset/versioned"
+	internalinterfaces "kluster/pkg/client/informers/externalversions/internalinterfaces"
+	v1alpha1 "kluster/pkg/client/listers/siqi.dev/v1alpha1"
+	time "time"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	watch "k8s.io/apimachinery/pkg/watch"
+	cache "k8s.io/client-go/tools/cache"
+)
+
+// KlusterCredentialsInformer provides access to a shared informer and lister for
+// KlusterCredentials.
+type KlusterCredentialsInformer interface {
+	Informer() cache.SharedIndexInformer
+	Lister() v1alpha1.KlusterCredentialsLister
+}
+
+type klusterCredentialsInformer struct {
+	factory          internalinterfaces.SharedInformerFactory
+	tweakListOptions internalinterfaces.TweakListOptionsFunc
+}
+
+// NewKlusterCredentialsInformer constructs a new informer for KlusterCredentials type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewKlusterCredentialsInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers) cache.SharedIndexInformer {
+	return NewFilteredKlusterCredentialsInformer(client, resyncPeriod, indexers, nil)
+}
+
+// NewFilteredKlusterCredentialsInformer constructs a new informer for KlusterCredentials type.
+// Always prefer using an informer factory to get a shared informer instead of getting an independent
+// one. This reduces memory footprint and number of connections to the server.
+func NewFilteredKlusterCredentialsInformer(client versioned.Interface, resyncPeriod time.Duration, indexers cache.Indexers, tweakListOptions internalinterfaces.TweakListOptionsFunc) cache.SharedIndexInformer {
+	return cache.NewSharedIndexInformer(
+		&cache.ListWatch{
+			ListFunc: func(options v1.ListOptions) (runtime.Object, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SiqiV1alpha1().KlusterCredentials().List(context.TODO(), options)
+			},
+			WatchFunc: func(options v1.ListOptions) (watch.Interface, error) {
+				if tweakListOptions != nil {
+					tweakListOptions(&options)
+				}
+				return client.SiqiV1alpha1().KlusterCredentials().Watch(context.TODO(), options)
+			},
+		},
+		&siqidevv1alpha1.KlusterCredentials{},
+		resyncPeriod,
+		indexers,
+	)
+}
+
+func (f *klusterCredentialsInformer) defaultInformer(client versioned.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	return NewFilteredKlusterCredentialsInformer(client, resyncPeriod, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}, f.tweakListOptions)
+}
+
+func (f *klusterCredentialsInformer) Informer() cache.SharedIndexInformer {
+	return f.factory.InformerFor(&siqidevv1alpha1.KlusterCredentials{}, f.defaultInformer)
+}
+
+func (f *klusterCredentialsInformer) Lister() v1alpha1.KlusterCredentialsLister {
+	return v1alpha1.NewKlusterCredentialsLister(f.Informer().GetIndexer())
+}
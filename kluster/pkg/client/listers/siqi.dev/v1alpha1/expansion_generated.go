@@ -25,3 +25,15 @@ type KlusterListerExpansion interface{}
 // KlusterNamespaceListerExpansion allows custom methods to be added to
 // KlusterNamespaceLister.
 type KlusterNamespaceListerExpansion interface{}
+
+// KlusterCredentialsListerExpansion allows custom methods to be added to
+// KlusterCredentialsLister.
+type KlusterCredentialsListerExpansion interface{}
+
+// KlusterReferenceGrantListerExpansion allows custom methods to be added to
+// KlusterReferenceGrantLister.
+type KlusterReferenceGrantListerExpansion interface{}
+
+// KlusterReferenceGrantNamespaceListerExpansion allows custom methods to be added to
+// KlusterReferenceGrantNamespaceLister.
+type KlusterReferenceGrantNamespaceListerExpansion interface{}
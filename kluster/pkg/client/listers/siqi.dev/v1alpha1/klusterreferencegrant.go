@@ -0,0 +1,100 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// KlusterReferenceGrantLister helps list KlusterReferenceGrants.
+// All objects returned here must be treated as read-only.
+type KlusterReferenceGrantLister interface {
+	// List lists all KlusterReferenceGrants in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterReferenceGrant, err error)
+	// KlusterReferenceGrants returns an object that can list and get KlusterReferenceGrants.
+	KlusterReferenceGrants(namespace string) KlusterReferenceGrantNamespaceLister
+	KlusterReferenceGrantListerExpansion
+}
+
+// klusterReferenceGrantLister implements the KlusterReferenceGrantLister interface.
+type klusterReferenceGrantLister struct {
+	indexer cache.Indexer
+}
+
+// NewKlusterReferenceGrantLister returns a new KlusterReferenceGrantLister.
+func NewKlusterReferenceGrantLister(indexer cache.Indexer) KlusterReferenceGrantLister {
+	return &klusterReferenceGrantLister{indexer: indexer}
+}
+
+// List lists all KlusterReferenceGrants in the indexer.
+func (s *klusterReferenceGrantLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterReferenceGrant, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterReferenceGrant))
+	})
+	return ret, err
+}
+
+// KlusterReferenceGrants returns an object that can list and get KlusterReferenceGrants.
+func (s *klusterReferenceGrantLister) KlusterReferenceGrants(namespace string) KlusterReferenceGrantNamespaceLister {
+	return klusterReferenceGrantNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// KlusterReferenceGrantNamespaceLister helps list and get KlusterReferenceGrants.
+// All objects returned here must be treated as read-only.
+type KlusterReferenceGrantNamespaceLister interface {
+	// List lists all KlusterReferenceGrants in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.KlusterReferenceGrant, err error)
+	// Get retrieves the KlusterReferenceGrant from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.KlusterReferenceGrant, error)
+	KlusterReferenceGrantNamespaceListerExpansion
+}
+
+// klusterReferenceGrantNamespaceLister implements the KlusterReferenceGrantNamespaceLister
+// interface.
+type klusterReferenceGrantNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all KlusterReferenceGrants in the indexer for a given namespace.
+func (s klusterReferenceGrantNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.KlusterReferenceGrant, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.KlusterReferenceGrant))
+	})
+	return ret, err
+}
+
+// Get retrieves the KlusterReferenceGrant from the indexer for a given namespace and name.
+func (s klusterReferenceGrantNamespaceLister) Get(name string) (*v1alpha1.KlusterReferenceGrant, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("klusterreferencegrant"), name)
+	}
+	return obj.(*v1alpha1.KlusterReferenceGrant), nil
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Prefix of the environment variables that set flags, e.g. KLUSTER_RESYNC_PERIOD sets -resync-period
+const EnvPrefix = "KLUSTER_"
+
+// EnvName is the environment variable that sets the flag with the given name
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Load parses args into the flags of fs and fills the flags that are not on the command line
+// from the environment and then from the YAML file named by the flag fileFlag.
+// The file maps flag names to values, lists are joined with commas.
+// The command line wins over the environment, which wins over the file.
+func Load(fs *flag.FlagSet, args []string, fileFlag string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(EnvName(f.Name))
+		if err != nil || set[f.Name] || !ok {
+			return
+		}
+		if e := fs.Set(f.Name, value); e != nil {
+			err = fmt.Errorf("setting -%s from %s: %w", f.Name, EnvName(f.Name), e)
+			return
+		}
+		set[f.Name] = true
+	})
+	if err != nil {
+		return err
+	}
+
+	file := fs.Lookup(fileFlag)
+	if file == nil || file.Value.String() == "" {
+		return nil
+	}
+	values, err := readFile(file.Value.String())
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag %q in %s", name, file.Value.String())
+		}
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("setting -%s from %s: %w", name, file.Value.String(), err)
+		}
+	}
+	return nil
+}
+
+// Read the flag values of a config file
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	data, err = yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	// Keep numbers as they are written, e.g. 1000000 instead of 1e+06
+	raw := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	for name, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			values[name] = ""
+		case []interface{}:
+			items := []string{}
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			values[name] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
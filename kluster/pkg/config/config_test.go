@@ -0,0 +1,121 @@
+package config
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		file    string /* Content of the config file, no file when empty */
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			want: map[string]string{"workers": "2", "resync-period": "10m0s", "namespaces": ""},
+		},
+		{
+			name: "file",
+			file: "workers: 4\nresync-period: 1m\nnamespaces: [team-a, team-b]\n",
+			want: map[string]string{"workers": "4", "resync-period": "1m0s", "namespaces": "team-a,team-b"},
+		},
+		{
+			name: "environment wins over the file",
+			env:  map[string]string{"KLUSTER_WORKERS": "6", "KLUSTER_RESYNC_PERIOD": "5m"},
+			file: "workers: 4\nnamespaces: [team-a]\n",
+			want: map[string]string{"workers": "6", "resync-period": "5m0s", "namespaces": "team-a"},
+		},
+		{
+			name: "flags win over the environment and the file",
+			args: []string{"-workers=8"},
+			env:  map[string]string{"KLUSTER_WORKERS": "6"},
+			file: "workers: 4\n",
+			want: map[string]string{"workers": "8"},
+		},
+		{
+			name: "large numbers of the file are kept as written",
+			file: "workers: 1000000\n",
+			want: map[string]string{"workers": "1000000"},
+		},
+		{
+			name:    "invalid flag",
+			args:    []string{"-workers=many"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid environment variable",
+			env:     map[string]string{"KLUSTER_RESYNC_PERIOD": "often"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid value in the file",
+			file:    "workers: many\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown flag in the file",
+			file:    "wrokers: 4\n",
+			wantErr: true,
+		},
+		{
+			name:    "file that is not YAML",
+			file:    "workers: [4\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Int("workers", 2, "")
+			fs.Duration("resync-period", 10*time.Minute, "")
+			fs.String("namespaces", "", "")
+			fs.String("config", "", "")
+
+			args := tt.args
+			if tt.file != "" {
+				path := filepath.Join(t.TempDir(), "config.yaml")
+				if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+					t.Fatalf("writing the config file: %v", err)
+				}
+				args = append([]string{"-config=" + path}, args...)
+			}
+
+			err := Load(fs, args, "config")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, want := range tt.want {
+				if got := fs.Lookup(name).Value.String(); got != want {
+					t.Errorf("-%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("config", "", "")
+	if err := Load(fs, []string{"-config=" + filepath.Join(t.TempDir(), "missing.yaml")}, "config"); err == nil {
+		t.Error("Load() of a missing config file did not fail")
+	}
+}
+
+func TestEnvName(t *testing.T) {
+	if got := EnvName("leader-elect-lease-duration"); got != "KLUSTER_LEADER_ELECT_LEASE_DURATION" {
+		t.Errorf("EnvName() = %q, want KLUSTER_LEADER_ELECT_LEASE_DURATION", got)
+	}
+}
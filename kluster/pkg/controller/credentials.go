@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/credentials"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Names of the indexes of klusters by the namespace/name and the namespace of their token secret and by their
+// KlusterCredentials, and of KlusterCredentials by the namespace/name of their secret
+const (
+	tokenSecretIndex          = "tokenSecret"
+	tokenSecretNamespaceIndex = "tokenSecretNamespace"
+	credentialsIndex          = "credentials"
+)
+
+// errReferenceNotGranted is returned for a token secret of another namespace that no KlusterReferenceGrant allows
+var errReferenceNotGranted = fmt.Errorf("%w: reference not granted", provider.ErrInvalidCredentials)
+
+// errSecretNotWatched is returned for a token secret in a namespace outside of -namespaces, the controller can not read it
+var errSecretNotWatched = fmt.Errorf("%w: secret not watched", provider.ErrInvalidCredentials)
+
+// Reason of the event and the CredentialsValid condition of errSecretNotWatched
+const secretNotWatched = "SecretNotWatched"
+
+// Index klusters by spec.tokenSecret, which is in the namespace/name form of secret keys
+func indexByTokenSecret(obj interface{}) ([]string, error) {
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok || kluster.Spec.TokenSecret == "" {
+		return nil, nil
+	}
+	return []string{kluster.Spec.TokenSecret}, nil
+}
+
+// Index klusters by the namespace of spec.tokenSecret when it is not their own
+func indexByTokenSecretNamespace(obj interface{}) ([]string, error) {
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok {
+		return nil, nil
+	}
+	namespace, _, ok := strings.Cut(kluster.Spec.TokenSecret, "/")
+	if !ok || namespace == kluster.Namespace {
+		return nil, nil
+	}
+	return []string{namespace}, nil
+}
+
+// Index klusters by spec.credentials
+func indexByCredentials(obj interface{}) ([]string, error) {
+	kluster, ok := obj.(*v1alpha1.Kluster)
+	if !ok || kluster.Spec.Credentials == "" {
+		return nil, nil
+	}
+	return []string{kluster.Spec.Credentials}, nil
+}
+
+// Index KlusterCredentials by the namespace/name of their secret
+func indexCredentialsBySecret(obj interface{}) ([]string, error) {
+	kc, ok := obj.(*v1alpha1.KlusterCredentials)
+	if !ok || kc.Spec.SecretRef == nil {
+		return nil, nil
+	}
+	return []string{kc.Spec.SecretRef.Namespace + "/" + kc.Spec.SecretRef.Name}, nil
+}
+
+// Secret handler: enqueue the klusters that read their token from the secret
+func (c *controller) handleSecret(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	klusters, err := c.klustersByIndex(tokenSecretIndex, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, kluster := range klusters {
+		klog.Infof("token secret %s changed, reconciling kluster %s\n", key, kluster.(*v1alpha1.Kluster).Name)
+		c.enqueue(kluster)
+	}
+
+	// Klusters can also read the secret through their KlusterCredentials
+	kcs, err := c.kcIndexer.ByIndex(credentialsIndex, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, kc := range kcs {
+		c.handleCredentials(kc)
+	}
+}
+
+// Secret update handler: only rotations of the data matter, resyncs and metadata changes are skipped
+func (c *controller) handleSecretUpdate(oldObj, newObj interface{}) {
+	oldSecret, ok := oldObj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	newSecret, ok := newObj.(*corev1.Secret)
+	if !ok {
+		return
+	}
+	if equality.Semantic.DeepEqual(oldSecret.Data, newSecret.Data) {
+		return
+	}
+	c.handleSecret(newObj)
+}
+
+// KlusterCredentials handler: enqueue the klusters that reference the credentials
+func (c *controller) handleCredentials(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	klusters, err := c.klustersByIndex(credentialsIndex, key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, kluster := range klusters {
+		klog.Infof("credentials %s changed, reconciling kluster %s\n", key, kluster.(*v1alpha1.Kluster).Name)
+		c.enqueue(kluster)
+	}
+}
+
+// KlusterReferenceGrant handler: enqueue the klusters of other namespaces that reference secrets
+// in the namespace of the grant, they may have been granted or lost the access
+func (c *controller) handleGrant(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	namespace, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	klusters, err := c.klustersByIndex(tokenSecretNamespaceIndex, namespace)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, kluster := range klusters {
+		klog.Infof("reference grant %s changed, reconciling kluster %s\n", key, kluster.(*v1alpha1.Kluster).Name)
+		c.enqueue(kluster)
+	}
+}
+
+// KlusterReferenceGrant update handler: resyncs and metadata changes are skipped
+func (c *controller) handleGrantUpdate(oldObj, newObj interface{}) {
+	oldGrant, ok := oldObj.(*v1alpha1.KlusterReferenceGrant)
+	if !ok {
+		return
+	}
+	newGrant, ok := newObj.(*v1alpha1.KlusterReferenceGrant)
+	if !ok {
+		return
+	}
+	if equality.Semantic.DeepEqual(oldGrant.Spec, newGrant.Spec) {
+		return
+	}
+	c.handleGrant(newObj)
+}
+
+// KlusterCredentials update handler: resyncs and metadata changes are skipped
+func (c *controller) handleCredentialsUpdate(oldObj, newObj interface{}) {
+	oldKC, ok := oldObj.(*v1alpha1.KlusterCredentials)
+	if !ok {
+		return
+	}
+	newKC, ok := newObj.(*v1alpha1.KlusterCredentials)
+	if !ok {
+		return
+	}
+	if equality.Semantic.DeepEqual(oldKC.Spec, newKC.Spec) {
+		return
+	}
+	c.handleCredentials(newObj)
+}
+
+// Build the provider of the kluster that acts with the kluster's own credentials,
+// so that workers reconciling klusters of different accounts never mix them up
+func (c *controller) providerFor(ctx context.Context, kluster *v1alpha1.Kluster) (provider.Provider, error) {
+	factory, err := c.providers.Get(kluster.Spec.Provider)
+	if err != nil {
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "UnknownProvider", err.Error())
+		return nil, err
+	}
+	creds, err := c.credentials(kluster)
+	if err != nil {
+		return nil, err
+	}
+	return factory.For(creds)
+}
+
+// Read the credentials from the source of the kluster, klusters without one get the credentials of the
+// default source of the controller, or empty ones and it is up to the provider whether it needs them
+func (c *controller) credentials(kluster *v1alpha1.Kluster) (provider.Credentials, error) {
+	source, err := c.credentialSource(kluster)
+	if err != nil {
+		return provider.Credentials{}, err
+	}
+	if source == nil {
+		return provider.Credentials{Kluster: kluster.UID}, nil
+	}
+	creds, err := source.Credentials()
+	if err != nil {
+		return provider.Credentials{}, err
+	}
+	creds.Kluster = kluster.UID
+	return creds, nil
+}
+
+// The source of the token of the kluster: its KlusterCredentials, its token secret or the default source.
+// The informers reconcile the kluster again once the secret or the KlusterCredentials show up or change.
+func (c *controller) credentialSource(kluster *v1alpha1.Kluster) (credentials.Source, error) {
+	if name := kluster.Spec.Credentials; name != "" {
+		kc, err := c.kcLister.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: getting KlusterCredentials %s: %v", provider.ErrInvalidCredentials, name, err)
+		}
+		if !credentialsAllowed(kc, kluster.Namespace) {
+			return nil, fmt.Errorf("%w: KlusterCredentials %s can not be used in namespace %s", provider.ErrInvalidCredentials, name, kluster.Namespace)
+		}
+		if ref := kc.Spec.SecretRef; ref != nil && !c.scope.Contains(ref.Namespace) {
+			message := fmt.Sprintf("Secret %s/%s of KlusterCredentials %s is in a namespace the controller does not watch", ref.Namespace, ref.Name, name)
+			c.recorder.Event(kluster, corev1.EventTypeWarning, secretNotWatched, message)
+			return nil, fmt.Errorf("%w: %s", errSecretNotWatched, message)
+		}
+		return c.sourceOf(kc), nil
+	}
+
+	if ref := kluster.Spec.TokenSecret; ref != "" {
+		namespace, name, ok := strings.Cut(ref, "/")
+		if !ok || namespace == "" || name == "" {
+			return nil, fmt.Errorf("%w: tokenSecret %q is not in the form namespace/name", provider.ErrInvalidCredentials, ref)
+		}
+		if !c.scope.Contains(namespace) {
+			message := fmt.Sprintf("tokenSecret %s is in a namespace the controller does not watch", ref)
+			c.recorder.Event(kluster, corev1.EventTypeWarning, secretNotWatched, message)
+			return nil, fmt.Errorf("%w: %s", errSecretNotWatched, message)
+		}
+		// The controller can read every secret, the kluster only gets those its namespace was granted
+		allowed, err := credentials.SecretReferenceAllowed(c.grantLister, kluster.Namespace, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: tokenSecret %s is in another namespace and no KlusterReferenceGrant of namespace %s allows namespace %s to reference it", errReferenceNotGranted, ref, namespace, kluster.Namespace)
+		}
+		key := kluster.Spec.TokenSecretKey
+		if key == "" {
+			key = c.opts.TokenSecretKey
+		}
+		return &credentials.Secret{Lister: c.sLister, Client: c.client.CoreV1(), Namespace: namespace, Name: name, Key: key}, nil
+	}
+
+	return c.opts.DefaultCredentials, nil
+}
+
+// The source the spec of the KlusterCredentials names, the API server makes sure it is exactly one.
+// Only cluster admins create KlusterCredentials, so their secret needs no KlusterReferenceGrant.
+func (c *controller) sourceOf(kc *v1alpha1.KlusterCredentials) credentials.Source {
+	switch {
+	case kc.Spec.SecretRef != nil:
+		key := kc.Spec.SecretRef.Key
+		if key == "" {
+			key = c.opts.TokenSecretKey
+		}
+		return &credentials.Secret{Lister: c.sLister, Client: c.client.CoreV1(), Namespace: kc.Spec.SecretRef.Namespace, Name: kc.Spec.SecretRef.Name, Key: key}
+	case kc.Spec.File != "":
+		return &credentials.File{Path: kc.Spec.File}
+	default:
+		return &credentials.Env{Name: kc.Spec.Env}
+	}
+}
+
+// Whether klusters of the namespace may use the KlusterCredentials, an empty allow-list allows no namespace
+func credentialsAllowed(kc *v1alpha1.KlusterCredentials, namespace string) bool {
+	for _, allowed := range kc.Spec.AllowedNamespaces {
+		if allowed == v1alpha1.AllNamespaces || allowed == namespace {
+			return true
+		}
+	}
+	return false
+}
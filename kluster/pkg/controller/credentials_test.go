@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	kfake "kluster/pkg/client/clientset/versioned/fake"
+	"kluster/pkg/provider"
+	"kluster/pkg/scope"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestCredentialsAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		want    bool
+	}{
+		{name: "no allowed namespaces", want: false},
+		{name: "listed namespace", allowed: []string{"team-b", "team-a"}, want: true},
+		{name: "other namespaces", allowed: []string{"team-b"}, want: false},
+		{name: "all namespaces", allowed: []string{v1alpha1.AllNamespaces}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kc := &v1alpha1.KlusterCredentials{Spec: v1alpha1.KlusterCredentialsSpec{Env: "DO_TOKEN", AllowedNamespaces: tt.allowed}}
+			if got := credentialsAllowed(kc, "team-a"); got != tt.want {
+				t.Errorf("credentialsAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialSourceOutsideOfTheScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    func(spec *v1alpha1.KlusterSpec)
+		wantErr error
+	}{
+		{
+			name:    "KlusterCredentials secret outside of the scope",
+			spec:    func(spec *v1alpha1.KlusterSpec) { spec.Credentials = "system-do" },
+			wantErr: errSecretNotWatched,
+		},
+		{
+			name: "KlusterCredentials secret in the scope",
+			spec: func(spec *v1alpha1.KlusterSpec) { spec.Credentials = "team-do" },
+		},
+		{
+			name:    "token secret outside of the scope",
+			spec:    func(spec *v1alpha1.KlusterSpec) { spec.TokenSecret = "team-b/dosecret" },
+			wantErr: errSecretNotWatched,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := newTestKluster()
+			tt.spec(&kluster.Spec)
+			klient := kfake.NewSimpleClientset(kluster)
+			s := scope.New(klient, kubefake.NewSimpleClientset(), []string{"team-a"}, "", "", 0)
+			c := NewController(kubefake.NewSimpleClientset(), klient, s, provider.Registry{}, Options{})
+			t.Cleanup(c.queue.ShutDown)
+			recorder := record.NewFakeRecorder(10)
+			c.recorder = recorder
+			for _, kc := range []*v1alpha1.KlusterCredentials{
+				{ObjectMeta: metav1.ObjectMeta{Name: "system-do"}, Spec: v1alpha1.KlusterCredentialsSpec{SecretRef: &v1alpha1.CredentialsSecretReference{Namespace: "kluster-system", Name: "dosecret"}, AllowedNamespaces: []string{"team-a"}}},
+				{ObjectMeta: metav1.ObjectMeta{Name: "team-do"}, Spec: v1alpha1.KlusterCredentialsSpec{SecretRef: &v1alpha1.CredentialsSecretReference{Namespace: "team-a", Name: "dosecret"}, AllowedNamespaces: []string{"team-a"}}},
+			} {
+				if err := s.Credentials().Informer().GetIndexer().Add(kc); err != nil {
+					t.Fatalf("adding the KlusterCredentials to the cache: %v", err)
+				}
+			}
+
+			_, err := c.credentialSource(kluster)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("credentialSource() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("credentialSource() error = %v, want %v", err, tt.wantErr)
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.Contains(event, secretNotWatched) {
+					t.Errorf("event %q, want reason %s", event, secretNotWatched)
+				}
+			default:
+				t.Error("no event was recorded")
+			}
+
+			c.recordError(context.Background(), kluster, err)
+			latest, _ := klient.SiqiV1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
+			cond := meta.FindStatusCondition(latest.Status.Conditions, v1alpha1.ConditionCredentialsValid)
+			if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != secretNotWatched {
+				t.Errorf("CredentialsValid condition = %+v, want false with reason %s", cond, secretNotWatched)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// Changes needed to make the cloud cluster match the kluster spec
+type clusterDiff struct {
+	version string              /* Version to upgrade to, empty if the version matches */
+	create  []v1alpha1.NodePool /* Node pools in the spec that are missing in the cloud */
+	remove  []string            /* Names of node pools in the cloud that were removed from the spec */
+	scale   []v1alpha1.NodePool /* Node pools whose count or autoscaling differs from the cloud */
+	resize  []v1alpha1.NodePool /* Node pools whose size differs from the cloud, they can not be resized in place */
+}
+
+func (d clusterDiff) empty() bool {
+	return d.version == "" && len(d.create) == 0 && len(d.remove) == 0 && len(d.scale) == 0 && len(d.resize) == 0
+}
+
+// Describe the changes for events and conditions
+func (d clusterDiff) String() string {
+	changes := []string{}
+	if d.version != "" {
+		changes = append(changes, "version differs from "+d.version)
+	}
+	for _, np := range d.create {
+		changes = append(changes, fmt.Sprintf("node pool %s is missing", np.Name))
+	}
+	for _, np := range d.scale {
+		changes = append(changes, fmt.Sprintf("node pool %s does not have the size of the spec", np.Name))
+	}
+	for _, np := range d.resize {
+		changes = append(changes, fmt.Sprintf("node pool %s does not have the size %s", np.Name, np.Size))
+	}
+	for _, name := range d.remove {
+		changes = append(changes, fmt.Sprintf("node pool %s is not in the spec", name))
+	}
+	return strings.Join(changes, ", ")
+}
+
+// Compare the kluster spec with the actual cloud cluster
+func computeDiff(spec v1alpha1.KlusterSpec, cluster *provider.Cluster) clusterDiff {
+	diff := clusterDiff{}
+
+	// An empty version or "latest" lets the provider pick, so there is nothing to compare
+	if spec.Version != "" && spec.Version != "latest" && spec.Version != cluster.Version {
+		diff.version = spec.Version
+	}
+
+	actual := map[string]provider.NodePool{}
+	for _, np := range cluster.NodePools {
+		actual[np.Name] = np
+	}
+	desired := map[string]bool{}
+	for _, np := range spec.NodePools {
+		desired[np.Name] = true
+		a, ok := actual[np.Name]
+		switch {
+		case !ok:
+			diff.create = append(diff.create, np)
+		case np.Size != "" && np.Size != a.Size:
+			diff.resize = append(diff.resize, np)
+		case needsScale(np, a):
+			diff.scale = append(diff.scale, np)
+		}
+	}
+	for _, np := range cluster.NodePools {
+		if !desired[np.Name] {
+			diff.remove = append(diff.remove, np.Name)
+		}
+	}
+
+	return diff
+}
+
+// The count of pools that scale automatically is up to the provider
+func needsScale(desired v1alpha1.NodePool, actual provider.NodePool) bool {
+	if desired.AutoScale != actual.AutoScale {
+		return true
+	}
+	if desired.AutoScale {
+		return desired.MinNodes != actual.MinNodes || desired.MaxNodes != actual.MaxNodes
+	}
+	return desired.Count != actual.Count
+}
+
+// Apply the changes of diff to the cloud cluster
+func (c *controller) applyDiff(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster, id string, diff clusterDiff) error {
+	// Create the new pools first so that workloads have somewhere to go when old pools are removed
+	for _, np := range diff.create {
+		klog.Infof("creating node pool %s of kluster %s\n", np.Name, kluster.Name)
+		if err := p.CreateNodePool(ctx, id, np); err != nil {
+			return fmt.Errorf("creating node pool %s: %w", np.Name, err)
+		}
+		c.recorder.Eventf(kluster, corev1.EventTypeNormal, "NodePoolCreated", "Node pool %s was created", np.Name)
+	}
+
+	for _, np := range diff.scale {
+		klog.Infof("scaling node pool %s of kluster %s to %d\n", np.Name, kluster.Name, np.Count)
+		if err := p.Scale(ctx, id, np); err != nil {
+			return fmt.Errorf("scaling node pool %s: %w", np.Name, err)
+		}
+		c.recorder.Eventf(kluster, corev1.EventTypeNormal, "NodePoolScaled", "Node pool %s was scaled to %d", np.Name, np.Count)
+	}
+
+	for _, name := range diff.remove {
+		klog.Infof("deleting node pool %s of kluster %s\n", name, kluster.Name)
+		if err := p.DeleteNodePool(ctx, id, name); err != nil {
+			return fmt.Errorf("deleting node pool %s: %w", name, err)
+		}
+		c.recorder.Eventf(kluster, corev1.EventTypeNormal, "NodePoolDeleted", "Node pool %s was deleted", name)
+	}
+
+	if diff.version != "" {
+		klog.Infof("upgrading kluster %s to %s\n", kluster.Name, diff.version)
+		if err := p.Upgrade(ctx, id, diff.version); err != nil {
+			return fmt.Errorf("upgrading to %s: %w", diff.version, err)
+		}
+		c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ClusterUpgrade", "Cluster upgrade to %s was started", diff.version)
+	}
+
+	// The webhook refuses size changes, so this is a pool that was replaced outside of the kluster
+	// or a spec from before the validation
+	if len(diff.resize) > 0 {
+		np := diff.resize[0]
+		return fmt.Errorf("node pool %s can not be resized to %s, add a node pool with the new size and remove this one", np.Name, np.Size)
+	}
+
+	return nil
+}
+
+// Report the state of each cloud node pool in the kluster status
+func nodePoolStatus(cluster *provider.Cluster) []v1alpha1.NodePoolStatus {
+	pools := []v1alpha1.NodePoolStatus{}
+	for _, np := range cluster.NodePools {
+		state := v1alpha1.NodePoolReady
+		switch {
+		case cluster.State == provider.StateProvisioning:
+			state = v1alpha1.NodePoolProvisioning
+		case np.Ready != np.Count:
+			state = v1alpha1.NodePoolScaling
+		}
+		pools = append(pools, v1alpha1.NodePoolStatus{
+			Name:       np.Name,
+			ID:         np.ID,
+			Size:       np.Size,
+			Count:      np.Count,
+			ReadyNodes: np.Ready,
+			State:      state,
+		})
+	}
+	return pools
+}
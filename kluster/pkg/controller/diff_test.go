@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+)
+
+func TestComputeDiff(t *testing.T) {
+	fixed := v1alpha1.NodePool{Name: "a", Size: "s-1vcpu-2gb", Count: 2}
+	autoscaled := v1alpha1.NodePool{Name: "b", Size: "s-2vcpu-4gb", Count: 1, AutoScale: true, MinNodes: 1, MaxNodes: 3}
+	cluster := &provider.Cluster{
+		Version: "1.27.6-do.0",
+		NodePools: []provider.NodePool{
+			{Name: "a", Size: "s-1vcpu-2gb", Count: 2},
+			{Name: "b", Size: "s-2vcpu-4gb", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 3},
+		},
+	}
+
+	with := func(np v1alpha1.NodePool, change func(np *v1alpha1.NodePool)) v1alpha1.NodePool {
+		change(&np)
+		return np
+	}
+
+	tests := []struct {
+		name  string
+		spec  v1alpha1.KlusterSpec
+		want  clusterDiff
+		empty bool
+	}{
+		{
+			name:  "in sync",
+			spec:  v1alpha1.KlusterSpec{Version: "1.27.6-do.0", NodePools: []v1alpha1.NodePool{fixed, autoscaled}},
+			empty: true,
+		},
+		{
+			name:  "latest version is not compared",
+			spec:  v1alpha1.KlusterSpec{Version: "latest", NodePools: []v1alpha1.NodePool{fixed, autoscaled}},
+			empty: true,
+		},
+		{
+			name: "version",
+			spec: v1alpha1.KlusterSpec{Version: "1.28.2-do.0", NodePools: []v1alpha1.NodePool{fixed, autoscaled}},
+			want: clusterDiff{version: "1.28.2-do.0"},
+		},
+		{
+			name: "count",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Count = 3 }), autoscaled}},
+			want: clusterDiff{scale: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Count = 3 })}},
+		},
+		{
+			name:  "count of an autoscaled pool is up to the provider",
+			spec:  v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{fixed, with(autoscaled, func(np *v1alpha1.NodePool) { np.Count = 5 })}},
+			empty: true,
+		},
+		{
+			name: "autoscale bounds",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{fixed, with(autoscaled, func(np *v1alpha1.NodePool) { np.MaxNodes = 5 })}},
+			want: clusterDiff{scale: []v1alpha1.NodePool{with(autoscaled, func(np *v1alpha1.NodePool) { np.MaxNodes = 5 })}},
+		},
+		{
+			name: "autoscale turned on",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.AutoScale, np.MinNodes, np.MaxNodes = true, 1, 4 }), autoscaled}},
+			want: clusterDiff{scale: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.AutoScale, np.MinNodes, np.MaxNodes = true, 1, 4 })}},
+		},
+		{
+			name: "size",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Size = "s-4vcpu-8gb" }), autoscaled}},
+			want: clusterDiff{resize: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Size = "s-4vcpu-8gb" })}},
+		},
+		{
+			name:  "empty size is not compared",
+			spec:  v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Size = "" }), autoscaled}},
+			empty: true,
+		},
+		{
+			name: "added pool",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{fixed, autoscaled, {Name: "c", Size: "s-1vcpu-2gb", Count: 1}}},
+			want: clusterDiff{create: []v1alpha1.NodePool{{Name: "c", Size: "s-1vcpu-2gb", Count: 1}}},
+		},
+		{
+			name: "removed pool",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{fixed}},
+			want: clusterDiff{remove: []string{"b"}},
+		},
+		{
+			name: "renamed pool",
+			spec: v1alpha1.KlusterSpec{NodePools: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Name = "c" }), autoscaled}},
+			want: clusterDiff{create: []v1alpha1.NodePool{with(fixed, func(np *v1alpha1.NodePool) { np.Name = "c" })}, remove: []string{"a"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff := computeDiff(tt.spec, cluster)
+			if diff.empty() != tt.empty {
+				t.Errorf("empty() = %v, want %v for %s", diff.empty(), tt.empty, diff)
+			}
+			if !tt.empty && !reflect.DeepEqual(diff, tt.want) {
+				t.Errorf("computeDiff() = %+v, want %+v", diff, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Whether a cloud cluster that drifted from the spec is brought back to it
+func correctsDrift(kluster *v1alpha1.Kluster) bool {
+	return kluster.Spec.DriftPolicy != v1alpha1.DriftPolicyReport
+}
+
+// The current spec was reconciled before, so differences to the cloud cluster were made outside of the kluster
+func specReconciled(kluster *v1alpha1.Kluster) bool {
+	return kluster.Status.ObservedGeneration == kluster.Generation
+}
+
+// Report a cloud cluster that no longer matches the reconciled spec, the event is only
+// recorded when the drift changed since it was last reported
+func (c *controller) reportDrift(kluster *v1alpha1.Kluster, cluster *provider.Cluster, reason, message string) error {
+	correcting := correctsDrift(kluster)
+	if correcting {
+		message += ", correcting it"
+	}
+	klog.Infof("kluster %s drifted: %s\n", kluster.Name, message)
+
+	cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDrifted)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Message != message {
+		c.recorder.Event(kluster, corev1.EventTypeWarning, reason, message)
+	}
+
+	return c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		if cluster != nil {
+			observeCluster(status, cluster, kluster.Generation)
+		}
+		setCondition(status, v1alpha1.ConditionDrifted, metav1.ConditionTrue, reason, message, kluster.Generation)
+		if !correcting {
+			setCondition(status, v1alpha1.ConditionReady, metav1.ConditionFalse, reason, message, kluster.Generation)
+		}
+	})
+}
+
+// Handle a cloud cluster that was deleted outside of the kluster, it is created again unless
+// the drift policy only reports it
+func (c *controller) handleMissingCluster(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster) (bool, error) {
+	message := fmt.Sprintf("Cluster %s was not found in the cloud", kluster.Status.KlusterID)
+	if err := c.reportDrift(kluster, nil, "ClusterMissing", message); err != nil {
+		return false, err
+	}
+	if !correctsDrift(kluster) {
+		return true, nil
+	}
+	return false, c.createCluster(ctx, p, kluster)
+}
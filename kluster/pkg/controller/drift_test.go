@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Let the kluster own a running cluster whose spec was reconciled, then scale the cluster outside of it
+func withScaledCluster(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+	withCluster(t, f, kluster)
+	kluster.Status.ObservedGeneration = kluster.Generation
+	pool := kluster.Spec.NodePools[0]
+	pool.Count = 5
+	if err := f.Scale(context.Background(), kluster.Status.KlusterID, pool); err != nil {
+		t.Fatalf("scaling the cluster: %v", err)
+	}
+}
+
+// Let the kluster own a running cluster whose spec was reconciled, then upgrade the cluster outside of it
+func withUpgradedCluster(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+	kluster.Spec.Version = "1.27.6-do.0"
+	withCluster(t, f, kluster)
+	kluster.Status.ObservedGeneration = kluster.Generation
+	if err := f.Upgrade(context.Background(), kluster.Status.KlusterID, "1.28.2-do.0"); err != nil {
+		t.Fatalf("upgrading the cluster: %v", err)
+	}
+}
+
+func TestDrift(t *testing.T) {
+	tests := []syncTest{
+		{
+			name:     "correct drift",
+			setup:    withScaledCluster,
+			requeued: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionDrifted) {
+					t.Errorf("conditions = %+v, want Drifted true", kluster.Status.Conditions)
+				}
+				cluster, _ := f.Get(context.Background(), kluster.Status.KlusterID)
+				if count := cluster.NodePools[0].Count; count != 2 {
+					t.Errorf("node pool a has %d nodes, want it scaled back to 2", count)
+				}
+			},
+		},
+		{
+			name: "report drift and keep the kubeconfig fresh",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withScaledCluster(t, f, kluster)
+				kluster.Spec.DriftPolicy = v1alpha1.DriftPolicyReport
+			},
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDrifted)
+				if cond == nil || cond.Reason != "DriftDetected" {
+					t.Errorf("Drifted condition = %+v, want reason DriftDetected", cond)
+				}
+				if kluster.Status.KubeConfigSecret != "k-kubeconfig" {
+					t.Errorf("status.kubeConfigSecret = %q, want k-kubeconfig", kluster.Status.KubeConfigSecret)
+				}
+				cluster, _ := f.Get(context.Background(), kluster.Status.KlusterID)
+				if count := cluster.NodePools[0].Count; count != 5 {
+					t.Errorf("node pool a has %d nodes, want the drift left alone", count)
+				}
+			},
+		},
+		{
+			name: "report a version drift instead of refusing a downgrade",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withUpgradedCluster(t, f, kluster)
+				kluster.Spec.DriftPolicy = v1alpha1.DriftPolicyReport
+			},
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDrifted)
+				if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "DriftDetected" {
+					t.Errorf("Drifted condition = %+v, want true with reason DriftDetected", cond)
+				}
+				if cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionUpgrading); cond != nil {
+					t.Errorf("Upgrading condition = %+v, want the version drift not treated as a downgrade", cond)
+				}
+			},
+		},
+		{
+			name:  "version drift that can not be corrected stays reported",
+			setup: withUpgradedCluster,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionDrifted) {
+					t.Errorf("conditions = %+v, want Drifted true", kluster.Status.Conditions)
+				}
+				cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionUpgrading)
+				if cond == nil || cond.Reason != "DowngradeRefused" {
+					t.Errorf("Upgrading condition = %+v, want the downgrade refused", cond)
+				}
+			},
+		},
+	}
+
+	runSyncTests(t, tests)
+}
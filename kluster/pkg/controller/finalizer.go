@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Finalizer owned by the controller, it keeps the kluster until its cloud cluster is deleted
+const klusterFinalizer = "siqi.dev/cluster-cleanup"
+
+// errDeletionBlocked is returned when the cloud cluster can not be deleted until an admin acts,
+// retrying does not help so the kluster waits for the informers to enqueue it again
+var errDeletionBlocked = errors.New("deletion blocked")
+
+func hasFinalizer(kluster *v1alpha1.Kluster) bool {
+	for _, f := range kluster.Finalizers {
+		if f == klusterFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// Add the finalizer before anything is created in the cloud and return the updated kluster
+func (c *controller) addFinalizer(kluster *v1alpha1.Kluster) (*v1alpha1.Kluster, error) {
+	if hasFinalizer(kluster) {
+		return kluster, nil
+	}
+
+	k := kluster.DeepCopy()
+	k.Finalizers = append(k.Finalizers, klusterFinalizer)
+	return c.klient.SiqiV1alpha1().Klusters(k.Namespace).Update(context.Background(), k, metav1.UpdateOptions{})
+}
+
+// Remove the finalizer so that the kluster can be deleted from the k8s cluster
+func (c *controller) removeFinalizer(kluster *v1alpha1.Kluster) error {
+	// get the latest version of kluster, the status was updated while deleting
+	k, err := c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	finalizers := []string{}
+	for _, f := range k.Finalizers {
+		if f != klusterFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	k.Finalizers = finalizers
+	_, err = c.klient.SiqiV1alpha1().Klusters(k.Namespace).Update(context.Background(), k, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete the cloud cluster of a kluster that is being deleted and release the finalizer once it is gone.
+// It returns false while the provider is still deleting the cluster.
+func (c *controller) finalize(ctx context.Context, kluster *v1alpha1.Kluster) (bool, error) {
+	if !hasFinalizer(kluster) {
+		return true, nil
+	}
+
+	id := kluster.Status.KlusterID
+	p, err := c.providerFor(ctx, kluster)
+	// Nothing can have been created for a provider that does not exist
+	if id == "" && errors.Is(err, provider.ErrUnknownProvider) {
+		return true, c.removeFinalizer(kluster)
+	}
+	if errors.Is(err, errReferenceNotGranted) {
+		return false, c.blockDeletion(kluster, err)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if id == "" {
+		// A create call may have reached the cloud without the ID being recorded, e.g. when the status
+		// update failed or the call timed out after the cloud accepted it, so look for an owned cluster
+		cluster, err := p.Find(ctx, kluster.Spec, provider.OwnerTag(kluster.UID))
+		if err != nil && !errors.Is(err, provider.ErrNotFound) {
+			return false, fmt.Errorf("looking up the cluster of the kluster: %w", err)
+		}
+		if err == nil {
+			klog.Infof("found cluster %s of kluster %s without a recorded ID\n", cluster.ID, kluster.Name)
+			id = cluster.ID
+		}
+	}
+
+	if id != "" {
+
+		// The delete call is made once, later passes check whether the cluster is gone
+		requested := false
+		if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionDeleting) {
+			requested = true
+			klog.Infof("deleting cluster %s of kluster %s\n", id, kluster.Name)
+			err = p.Delete(ctx, id)
+			if err != nil && !errors.Is(err, provider.ErrNotFound) {
+				c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterDeletionFailed", err.Error())
+				return false, fmt.Errorf("deleting cluster %s: %w", id, err)
+			}
+			c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterDeletion", "Provider API was called to delete the cluster")
+
+			err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+				// Later passes check the cluster that was found instead of looking it up again
+				status.KlusterID = id
+				status.Progress = "deleting"
+				setCondition(status, v1alpha1.ConditionDeleting, metav1.ConditionTrue, "ClusterDeleting", "The cluster is being deleted", kluster.Generation)
+				setCondition(status, v1alpha1.ConditionReady, metav1.ConditionFalse, "ClusterDeleting", "The cluster is being deleted", kluster.Generation)
+			})
+			if err != nil {
+				return false, err
+			}
+		}
+
+		// Only release the kluster once the provider confirms the cluster is gone
+		gone, err := c.clusterGone(ctx, p, kluster, id, requested)
+		if err != nil || !gone {
+			return false, err
+		}
+		klog.Infof("Cluster %s was deleted succcessfully", id)
+		c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterDeletionCompleted", "Cluster deletion was completed")
+	}
+
+	return true, c.removeFinalizer(kluster)
+}
+
+// Report that the cloud cluster can not be deleted because the grant of the token secret was revoked.
+// The grant informer reconciles the kluster again once a KlusterReferenceGrant allows the secret.
+func (c *controller) blockDeletion(kluster *v1alpha1.Kluster, reason error) error {
+	const blocked = "ReferenceNotGranted"
+	if cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDeleting); cond == nil || cond.Reason != blocked {
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "DeletionBlocked", "The cluster can not be deleted until a KlusterReferenceGrant allows the token secret again")
+	}
+	err := c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		setCondition(status, v1alpha1.ConditionDeleting, metav1.ConditionFalse, blocked, "The cluster can not be deleted until a KlusterReferenceGrant allows the token secret again", kluster.Generation)
+		setCondition(status, v1alpha1.ConditionCredentialsValid, metav1.ConditionFalse, "InvalidCredentials", reason.Error(), kluster.Generation)
+	})
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %v", errDeletionBlocked, reason)
+}
+
+// Check whether the provider does not know the cluster anymore. A cluster that is not being deleted although
+// the provider accepted the delete call of an earlier pass, e.g. because the deletion failed, is deleted again.
+func (c *controller) clusterGone(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster, clusterID string, requested bool) (bool, error) {
+	cluster, err := p.Get(ctx, clusterID)
+	if errors.Is(err, provider.ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	switch {
+	case cluster.State == provider.StateDeleted:
+		return true, nil
+	case cluster.State == provider.StateDeleting || requested:
+		return false, nil
+	}
+
+	klog.Infof("cluster %s of kluster %s is %s instead of deleting, deleting it again\n", clusterID, kluster.Name, cluster.State)
+	err = p.Delete(ctx, clusterID)
+	if err != nil && !errors.Is(err, provider.ErrNotFound) {
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterDeletionFailed", err.Error())
+		return false, fmt.Errorf("deleting cluster %s again: %w", clusterID, err)
+	}
+	c.recorder.Eventf(kluster, corev1.EventTypeWarning, "ClusterDeletionRetried", "Cluster was %s instead of being deleted, the provider API was called to delete it again", cluster.State)
+	return false, nil
+}
@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+	"kluster/pkg/provider/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFinalize(t *testing.T) {
+	tests := []syncTest{
+		{
+			name: "delete the cluster",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withCluster(t, f, kluster)
+				deleting(kluster)
+			},
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if hasFinalizer(kluster) {
+					t.Error("the finalizer was not removed")
+				}
+				if _, err := f.Get(context.Background(), "fake-1"); !errors.Is(err, provider.ErrNotFound) {
+					t.Errorf("the cluster was not deleted: %v", err)
+				}
+			},
+		},
+		{
+			name: "finalize waits for the cluster to be gone",
+			opts: fake.Options{DeletionDelay: time.Hour},
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withCluster(t, f, kluster)
+				deleting(kluster)
+			},
+			requeued: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !hasFinalizer(kluster) {
+					t.Error("the finalizer was removed before the cluster was gone")
+				}
+				if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionDeleting) {
+					t.Errorf("conditions = %+v, want Deleting true", kluster.Status.Conditions)
+				}
+			},
+		},
+		{
+			name: "delete a cluster again that is not deleting after the delete was accepted",
+			opts: fake.Options{DeletionDelay: time.Hour},
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withCluster(t, f, kluster)
+				deleting(kluster)
+				meta.SetStatusCondition(&kluster.Status.Conditions, metav1.Condition{Type: v1alpha1.ConditionDeleting, Status: metav1.ConditionTrue, Reason: "ClusterDeleting"})
+			},
+			requeued: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !hasFinalizer(kluster) {
+					t.Error("the finalizer was removed before the cluster was gone")
+				}
+				cluster, err := f.Get(context.Background(), "fake-1")
+				if err != nil || cluster.State != provider.StateDeleting {
+					t.Errorf("cluster = %+v, %v, want it deleting", cluster, err)
+				}
+			},
+		},
+		{
+			name: "finalize a kluster whose cluster ID was not recorded",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				createCluster(t, f, kluster)
+				deleting(kluster)
+			},
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if hasFinalizer(kluster) {
+					t.Error("the finalizer was not removed")
+				}
+				if _, err := f.Find(context.Background(), kluster.Spec, provider.OwnerTag(kluster.UID)); !errors.Is(err, provider.ErrNotFound) {
+					t.Errorf("the cluster found by its owner tag was not deleted: %v", err)
+				}
+			},
+		},
+		{
+			name:  "finalize a kluster without a cluster",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) { deleting(kluster) },
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if hasFinalizer(kluster) {
+					t.Error("the finalizer was not removed")
+				}
+			},
+		},
+		{
+			name: "blocked without a grant for the token secret",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				withCluster(t, f, kluster)
+				kluster.Spec.TokenSecret = "team-b/dosecret"
+				deleting(kluster)
+			},
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !hasFinalizer(kluster) {
+					t.Error("the finalizer was removed without deleting the cluster")
+				}
+				cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionDeleting)
+				if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "ReferenceNotGranted" {
+					t.Errorf("Deleting condition = %+v, want false with reason ReferenceNotGranted", cond)
+				}
+			},
+		},
+	}
+
+	runSyncTests(t, tests)
+}
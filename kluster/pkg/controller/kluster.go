@@ -2,44 +2,67 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"kluster/pkg/apis/siqi.dev/v1alpha1"
 	klientset "kluster/pkg/client/clientset/versioned"
 	skeme "kluster/pkg/client/clientset/versioned/scheme"
-	kinf "kluster/pkg/client/informers/externalversions/siqi.dev/v1alpha1"
 	klister "kluster/pkg/client/listers/siqi.dev/v1alpha1"
-	"kluster/pkg/do"
+	"kluster/pkg/credentials"
+	"kluster/pkg/metrics"
+	"kluster/pkg/provider"
+	"kluster/pkg/scope"
 
-	"github.com/kanisterio/kanister/pkg/poll"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 )
 
+// How long to wait before looking at a cluster again while the cloud is still working on it
+const requeueInterval = 15 * time.Second
+
 type controller struct {
-	client        kubernetes.Interface            /* Client set to store and pass the secrete token */
-	klient        klientset.Interface             /* Customized crd kluster klient */
-	kLister       klister.KlusterLister           /* Component of informer to get the resources from cache */
-	klusterSynced cache.InformerSynced            /* To get Status that if the cache is successfully synced, passed from reflector */
-	queue         workqueue.RateLimitingInterface /* FIFO queue so we can add objects to queue when Add/delete functions are called */
-	recorder      record.EventRecorder            /* Event recorder for the cr */
+	client      kubernetes.Interface                /* Client set to store and pass the secrete token */
+	klient      klientset.Interface                 /* Customized crd kluster klient */
+	scope       *scope.Scope                        /* Namespaces the klusters are managed in, with their informers */
+	kLister     klister.KlusterLister               /* Component of informer to get the resources from cache */
+	kcLister    klister.KlusterCredentialsLister    /* Cache of the KlusterCredentials klusters reference */
+	kcIndexer   cache.Indexer                       /* Cache of KlusterCredentials indexed by their secret */
+	grantLister klister.KlusterReferenceGrantLister /* Cache of the grants to reference secrets of other namespaces */
+	sLister     corelisters.SecretLister            /* Cache of the secrets the tokens are read from */
+	queue       workqueue.RateLimitingInterface     /* FIFO queue of namespace/name keys added when Add/update/delete functions are called */
+	recorder    record.EventRecorder                /* Event recorder for the cr */
+	providers   provider.Registry                   /* Cloud providers selected by spec.provider */
+	opts        Options                             /* Timeouts of the workers */
+
+	mu         sync.Mutex           /* Guards processing */
+	processing map[string]time.Time /* Keys the workers are processing and since when */
 }
 
-var clusterID string
+// Options tune how the workers call the cloud, where they read tokens from and how they shut down
+type Options struct {
+	ProviderTimeout time.Duration /* Time one reconcile may spend calling the cloud, 0 means no limit */
+	ShutdownTimeout time.Duration /* Time the workers get on shutdown to finish the queued klusters before their cloud calls are cancelled */
+
+	TokenSecretKey     string             /* Key of the token in secrets that do not name one, empty is "token" */
+	DefaultCredentials credentials.Source /* Source of the token of klusters without tokenSecret and credentials, nil gives them none */
+}
 
-// Create new controllers
-func NewController(client kubernetes.Interface, klient klientset.Interface, klusterInformer kinf.KlusterInformer) *controller {
+// Create new controllers that manage the klusters of the namespaces in the scope
+func NewController(client kubernetes.Interface, klient klientset.Interface, s *scope.Scope, providers provider.Registry, opts Options) *controller {
 	runtime.Must(skeme.AddToScheme(scheme.Scheme))
 	eveBroadCaster := record.NewBroadcaster()
 	eveBroadCaster.StartStructuredLogging(0)
@@ -49,59 +72,136 @@ func NewController(client kubernetes.Interface, klient klientset.Interface, klus
 	recorder := eveBroadCaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "Kluster"})
 
 	c := &controller{
-		client:        client,
-		klient:        klient,
-		kLister:       klusterInformer.Lister(),
-		klusterSynced: klusterInformer.Informer().HasSynced,
-		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kluster"),
-		recorder:      recorder,
+		client:      client,
+		klient:      klient,
+		scope:       s,
+		kLister:     s.KlusterLister(),
+		kcLister:    s.Credentials().Lister(),
+		kcIndexer:   s.Credentials().Informer().GetIndexer(),
+		grantLister: s.GrantLister(),
+		sLister:     s.SecretLister(),
+		queue:       workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "kluster"),
+		recorder:    recorder,
+		providers:   providers,
+		opts:        opts,
+		processing:  map[string]time.Time{},
 	}
 
-	// Register functions in informer to handle add/delete events
-	klusterInformer.Informer().AddEventHandler(
+	for _, ns := range s.Namespaces() {
+		// Register functions in informer to handle add/update/delete events
+		ns.Klusters.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.handleAdd,
+				UpdateFunc: c.handleUpdate,
+				DeleteFunc: c.handleDel,
+			},
+		)
+
+		// Reconcile the klusters of a token secret or KlusterCredentials again when it is created, rotated or deleted
+		runtime.Must(ns.Klusters.Informer().AddIndexers(cache.Indexers{
+			tokenSecretIndex:          indexByTokenSecret,
+			tokenSecretNamespaceIndex: indexByTokenSecretNamespace,
+			credentialsIndex:          indexByCredentials,
+		}))
+		ns.Secrets.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.handleSecret,
+				UpdateFunc: c.handleSecretUpdate,
+				DeleteFunc: c.handleSecret,
+			},
+		)
+		// and the klusters that reference secrets of other namespaces when the grants of those namespaces change
+		ns.Grants.Informer().AddEventHandler(
+			cache.ResourceEventHandlerFuncs{
+				AddFunc:    c.handleGrant,
+				UpdateFunc: c.handleGrantUpdate,
+				DeleteFunc: c.handleGrant,
+			},
+		)
+	}
+	runtime.Must(s.Credentials().Informer().AddIndexers(cache.Indexers{credentialsIndex: indexCredentialsBySecret}))
+	s.Credentials().Informer().AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
-			AddFunc:    c.handleAdd,
-			DeleteFunc: c.handleDel,
+			AddFunc:    c.handleCredentials,
+			UpdateFunc: c.handleCredentialsUpdate,
+			DeleteFunc: c.handleCredentials,
 		},
 	)
 	return c
 }
 
-// Run controllers: sync cache and keep running workers until the channel is closed.
-func (c *controller) Run(workers int, ch <-chan struct{}) error {
-	defer runtime.HandleCrash()
+// The klusters of all namespaces in the scope whose index matches the value
+func (c *controller) klustersByIndex(index, value string) ([]interface{}, error) {
+	var all []interface{}
+	for _, ns := range c.scope.Namespaces() {
+		klusters, err := ns.Klusters.Informer().GetIndexer().ByIndex(index, value)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, klusters...)
+	}
+	return all, nil
+}
 
-	// As long as the Shutdown is called, the processItem method will return false
-	defer c.queue.ShutDown()
+// Run controllers: sync cache and keep running workers until ctx is done.
+// On shutdown the workers finish the queued klusters, their cloud calls are cancelled
+// when that takes longer than the shutdown timeout.
+func (c *controller) Run(ctx context.Context, workers int) error {
+	defer runtime.HandleCrash()
 	klog.Infof("start controller")
 
 	// Make sure informer cache has been synced
-	if !cache.WaitForCacheSync(ch, c.klusterSynced) {
+	if !cache.WaitForCacheSync(ctx.Done(), c.scope.HasSynced) {
+		c.queue.ShutDown()
 		klog.Errorf("failed to wait for caches to sync")
 		return fmt.Errorf("failed to wait for caches to sync")
 	}
 
+	// The workers do not use ctx, so that the klusters they are working on are not interrupted right away
+	workCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stopped := make(chan struct{})
+	var wg sync.WaitGroup
 	for i := 0; i < workers; i++ {
-		go wait.Until(c.worker, 1*time.Second, ch)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.worker(workCtx)
+		}()
 	}
-
-	// go routine is non-blocking.
-	// This step is to make the channel keep waiting
-	// if we do not put something into the channel.
-	<-ch
+	go func() {
+		wg.Wait()
+		close(stopped)
+	}()
+
+	<-ctx.Done()
+	klog.Infof("shutting down, draining the workqueue\n")
+	// The queue still hands out the queued keys but drops the keys added from now on,
+	// once it is empty the workers return
+	c.queue.ShutDown()
+
+	select {
+	case <-stopped:
+	case <-time.After(c.opts.ShutdownTimeout):
+		klog.Infof("workqueue was not drained after %s, cancelling the calls to the cloud\n", c.opts.ShutdownTimeout)
+		cancel()
+		<-stopped
+	}
+	klog.Infof("controller stopped")
 	return nil
 }
 
-// The worker will keep running processItem until it returns false
-func (c *controller) worker() {
-	for c.processItem() {
+// The worker will keep running processItem until the queue is shut down and empty
+func (c *controller) worker(ctx context.Context) {
+	for c.processItem(ctx) {
 
 	}
 }
 
 // Poll from the queue and then sync deployment
 // Production: Refer to claimWorker method in kubenetes library
-func (c *controller) processItem() bool {
+func (c *controller) processItem(ctx context.Context) bool {
 	item, shutdown := c.queue.Get()
 	if shutdown {
 		return false
@@ -110,8 +210,15 @@ func (c *controller) processItem() bool {
 	err := func(obj interface{}) error {
 		defer c.queue.Done(obj)
 
-		if err := c.syncHandler(obj); err != nil {
-			return fmt.Errorf("error syncing '%s': %s", obj, err.Error())
+		key, ok := obj.(string)
+		if !ok {
+			c.queue.Forget(obj)
+			return fmt.Errorf("expected string in queue but got %#v", obj)
+		}
+		c.startProcessing(key)
+		defer c.stopProcessing(key)
+		if err := c.syncHandler(ctx, key); err != nil {
+			return fmt.Errorf("error syncing '%s': %s", key, err.Error())
 		}
 
 		c.queue.Forget(obj)
@@ -127,12 +234,40 @@ func (c *controller) processItem() bool {
 	return true
 }
 
-// Handle add and delete event sync
-func (c *controller) syncHandler(item interface{}) error {
-	key, err := cache.MetaNamespaceKeyFunc(item)
-	if err != nil {
-		klog.Errorf("getting key from cache %s\n", err.Error())
+// Ready reports whether the caches of the scope are synced, the workers only act on synced caches
+func (c *controller) Ready() error {
+	return c.scope.Synced()
+}
+
+// Healthy reports whether a worker has been processing a key for longer than timeout,
+// e.g. because a call to the cloud hangs
+func (c *controller) Healthy(timeout time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, since := range c.processing {
+		if d := time.Since(since); d > timeout {
+			return fmt.Errorf("worker is stuck on kluster %s for %s", key, d.Round(time.Second))
+		}
 	}
+	return nil
+}
+
+// Record that a worker started processing key, the queue never hands the same key to two workers
+func (c *controller) startProcessing(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processing[key] = time.Now()
+}
+
+func (c *controller) stopProcessing(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.processing, key)
+}
+
+// Handle add, update and delete event sync
+func (c *controller) syncHandler(ctx context.Context, key string) error {
 	ns, name, err := cache.SplitMetaNamespaceKey(key)
 
 	if err != nil {
@@ -144,130 +279,310 @@ func (c *controller) syncHandler(item interface{}) error {
 	kluster, err := c.kLister.Klusters(ns).Get(name)
 	if err != nil {
 
-		// If error is that the object is not found in k8s cluster, i.e. the event is deletion
+		// If error is that the object is not found in k8s cluster, the finalizer already cleaned up the cloud cluster
 		if apierrors.IsNotFound(err) {
 			klog.Infof("kluster %s was deleted\n", name)
-
-			return deleteDOCluster(clusterID)
+			metrics.ForgetKluster(ns, name)
+			return nil
 		}
 		klog.Errorf("error %s, Getting the kluster resource from lister", err.Error())
 		return err
 	}
 
-	klog.Infof("kluster spec that we have is %+v\n", kluster.Spec)
+	start := time.Now()
+	if c.opts.ProviderTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opts.ProviderTimeout)
+		defer cancel()
+	}
 
-	clusterID, err = do.Create(c.client, kluster.Spec)
-	klog.Infof("clusterID is %+s\n", clusterID)
+	// The kluster is being deleted, remove its cloud cluster before letting it go
+	if kluster.DeletionTimestamp != nil {
+		done, err := c.finalize(ctx, kluster)
+		if errors.Is(err, errDeletionBlocked) {
+			klog.Infof("deletion of kluster %s is blocked: %s\n", name, err.Error())
+			metrics.ObserveReconcile(ns, name, metrics.ResultError, start)
+			c.recordError(ctx, kluster, err)
+			c.queue.Forget(key)
+			return nil
+		}
+		if err != nil {
+			klog.Errorf("error %s, deleting the cluster\n", err.Error())
+			metrics.ObserveReconcile(ns, name, metrics.ResultError, start)
+			c.recordError(ctx, kluster, err)
+			c.retry(err, key)
+			return err
+		}
+		if !done {
+			metrics.ObserveReconcile(ns, name, metrics.ResultRequeue, start)
+			c.queue.AddAfter(key, requeueInterval)
+			return nil
+		}
+		metrics.ObserveReconcile(ns, name, metrics.ResultSuccess, start)
+		return nil
+	}
+
+	done, err := c.reconcile(ctx, kluster)
 	if err != nil {
-		klog.Errorf("error %s, creating the cluster\n", err.Error())
-		c.retry(err, item)
+		klog.Errorf("error %s, reconciling kluster %s\n", err.Error(), name)
+		metrics.ObserveReconcile(ns, name, metrics.ResultError, start)
+		c.recordError(ctx, kluster, err)
+		c.retry(err, key)
 		return err
 	}
+	// The cloud is still working on the cluster, look at it again later instead of blocking the worker
+	if !done {
+		metrics.ObserveReconcile(ns, name, metrics.ResultRequeue, start)
+		c.queue.AddAfter(key, requeueInterval)
+		return nil
+	}
+	metrics.ObserveReconcile(ns, name, metrics.ResultSuccess, start)
+	return nil
+}
 
-	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreation", "DO API was called to create the cluster")
+// Create the cloud cluster of the kluster or bring the existing one in line with the spec.
+// It returns false while the cluster is still being created or updated.
+func (c *controller) reconcile(ctx context.Context, kluster *v1alpha1.Kluster) (bool, error) {
+	klog.Infof("kluster spec that we have is %+v\n", kluster.Spec)
 
-	err = c.updateStatus(clusterID, "creating", kluster)
+	p, err := c.providerFor(ctx, kluster)
 	if err != nil {
-		klog.Errorf("error %s, updating the status of the kluster %s\n", err.Error(), kluster.Name)
-		return err
+		return false, err
 	}
 
-	// Query DO API to make sure the cluster is created
-	err = c.waitForCluster(kluster.Spec, clusterID)
+	// Make sure we get the chance to delete the cloud cluster before the kluster is gone
+	kluster, err = c.addFinalizer(kluster)
 	if err != nil {
-		klog.Errorf("Cluster is already deleted")
-		return err
+		return false, fmt.Errorf("adding the finalizer: %w", err)
 	}
 
-	err = c.updateStatus(clusterID, "running", kluster)
-	if err != nil {
-		// In prod env, we need to retry if a kluster is not created successfully
-		klog.Errorf("error %s, updating the status of the kluster %s after waiting\n", err.Error(), kluster.Name)
-		return err
+	// The cluster was created before, bring it in line with the spec
+	if kluster.Status.KlusterID != "" {
+		return c.reconcileCluster(ctx, p, kluster)
 	}
+	return false, c.createCluster(ctx, p, kluster)
+}
 
-	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreationCompleted", "DO cluster creation was completed")
+// Create the cloud cluster and record its ID, the next passes check whether it is running
+func (c *controller) createCluster(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster) error {
+	clusterID, err := c.createOrAdopt(ctx, p, kluster)
+	if err != nil {
+		return fmt.Errorf("creating the cluster: %w", err)
+	}
+	klog.Infof("clusterID is %+s\n", clusterID)
 
+	err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		status.KlusterID = clusterID
+		status.Progress = "creating"
+		acceptCredentials(status, kluster.Generation)
+		setCondition(status, v1alpha1.ConditionProvisioning, metav1.ConditionTrue, "ClusterCreating", "The cluster is being created", kluster.Generation)
+		setCondition(status, v1alpha1.ConditionReady, metav1.ConditionFalse, "ClusterCreating", "The cluster is being created", kluster.Generation)
+	})
+	if err != nil {
+		return fmt.Errorf("updating the status: %w", err)
+	}
 	return nil
 }
 
-// Retry for five times if failed to sync deployment
-func (c *controller) retry(err error, key interface{}) {
+// Create the cloud cluster of a kluster without an ID, unless a previous attempt already created it.
+// This happens when the controller restarts or fails to record the ID after creating the cluster.
+func (c *controller) createOrAdopt(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster) (string, error) {
+	owner := provider.OwnerTag(kluster.UID)
+	cluster, err := p.Find(ctx, kluster.Spec, owner)
 	if err == nil {
-		// Item is successfully processed.
-		c.queue.Forget(key)
-		return
+		c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ClusterAdopted", "Existing cluster %s owned by the kluster was adopted", cluster.ID)
+		return cluster.ID, nil
 	}
-	// If item is not successfully processed,
-	// check how many times you have retried until 5 times
-	if c.queue.NumRequeues(key) < 5 {
-		klog.Infof("Error syncing: %v\n", err)
-		c.queue.AddRateLimited(key)
-		return
+	if !errors.Is(err, provider.ErrNotFound) {
+		return "", fmt.Errorf("looking up existing cluster: %w", err)
 	}
 
-	// If you have reached the 5 limit times of retry, forget this item
-	c.queue.Forget(key)
-	// report error
-	runtime.HandleError(err)
-	klog.Errorf("Dropping pod %q out of the queue: %v", key, err)
+	id, err := p.Create(ctx, kluster.Spec, owner)
+	if err != nil {
+		return "", err
+	}
+	c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreation", "Provider API was called to create the cluster")
+	return id, nil
 }
 
-// Delete actual cluster from digital ocean
-func deleteDOCluster(klusterID string) error {
-	err := do.Delete(klusterID)
-	klog.Infof("ClusterID: %s", klusterID)
+// Compute the diff between the spec and the cloud cluster and apply it.
+// It returns true once the running cluster matches the spec.
+func (c *controller) reconcileCluster(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster) (bool, error) {
+	id := kluster.Status.KlusterID
+	cluster, err := p.Get(ctx, id)
+	if errors.Is(err, provider.ErrNotFound) {
+		return c.handleMissingCluster(ctx, p, kluster)
+	}
 	if err != nil {
-		klog.Errorf("error %s, destroying the cluster\n", err.Error())
-		return err
+		return false, fmt.Errorf("getting cluster %s: %w", id, err)
 	}
-	klog.Infof("Cluster was deleted succcessfully")
 
-	return nil
-}
+	// Node pools can not be resized and clusters can not be upgraded while they are busy,
+	// so only record the state and check again later
+	if cluster.State != provider.StateRunning {
+		klog.Infof("cluster %s of kluster %s is %s\n", id, kluster.Name, cluster.State)
+		err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+			// Keep the progress the controller set until the cluster is running
+			if status.Progress != "creating" && status.Progress != "updating" {
+				status.Progress = cluster.State
+			}
+			observeCluster(status, cluster, kluster.Generation)
+			acceptCredentials(status, kluster.Generation)
+		})
+		return false, err
+	}
 
-// Wait for cluster to finish creating
-func (c *controller) waitForCluster(spec v1alpha1.KlusterSpec, clusterID string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
-	defer cancel()
+	diff := computeDiff(kluster.Spec, cluster)
 
-	return poll.Wait(ctx, func(ctx context.Context) (bool, error) {
-		state, err := do.ClusterState(clusterID)
-		if err != nil {
+	// Changes to the cloud cluster made outside of the kluster. This includes a version that differs
+	// from a reconciled spec, which is drift and not a request to upgrade or downgrade the cluster.
+	drifted := !diff.empty() && specReconciled(kluster)
+	if drifted {
+		if err := c.reportDrift(kluster, cluster, "DriftDetected", "Cluster does not match the spec: "+diff.String()); err != nil {
 			return false, err
 		}
-		if state == "running" {
+		// The cluster is left as it is, but its kubeconfig is still kept fresh
+		if !correctsDrift(kluster) {
+			if err := c.syncKubeConfig(ctx, p, kluster, id); err != nil {
+				return false, err
+			}
 			return true, nil
 		}
+	}
+
+	if diff.version != "" {
+		ok, err := c.validateUpgrade(ctx, p, kluster, cluster, diff.version)
+		if err != nil {
+			return false, err
+		}
+		// The rest of the spec is still applied when the version is refused
+		if !ok {
+			diff.version = ""
+		}
+	}
 
-		return false, nil
+	if diff.empty() {
+		klog.Infof("kluster %s is up to date\n", kluster.Name)
+		if err := c.syncKubeConfig(ctx, p, kluster, id); err != nil {
+			return false, err
+		}
+		if kluster.Status.Progress == "creating" {
+			c.recorder.Event(kluster, corev1.EventTypeNormal, "ClusterCreationCompleted", "Cluster creation was completed")
+		}
+		upgraded := meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionUpgrading)
+		if upgraded {
+			c.recorder.Eventf(kluster, corev1.EventTypeNormal, "ClusterUpgradeCompleted", "Cluster was upgraded to %s", cluster.Version)
+		}
+		return true, c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+			status.Progress = cluster.State
+			observeCluster(status, cluster, kluster.Generation)
+			markReconciled(status, kluster.Generation)
+			// A drifted version that can not be corrected is still reported
+			if !drifted {
+				setCondition(status, v1alpha1.ConditionDrifted, metav1.ConditionFalse, "InSync", "The cluster matches the spec", kluster.Generation)
+			}
+			if upgraded {
+				setCondition(status, v1alpha1.ConditionUpgrading, metav1.ConditionFalse, "UpgradeCompleted", "The cluster runs "+cluster.Version, kluster.Generation)
+			}
+		})
+	}
+
+	err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		status.Progress = "updating"
+		observeCluster(status, cluster, kluster.Generation)
+		setCondition(status, v1alpha1.ConditionReady, metav1.ConditionFalse, "ClusterUpdating", "The cluster is being updated to match the spec", kluster.Generation)
 	})
+	if err != nil {
+		return false, err
+	}
+	if err := c.applyDiff(ctx, p, kluster, id, diff); err != nil {
+		c.recorder.Event(kluster, corev1.EventTypeWarning, "ClusterUpdateFailed", err.Error())
+		return false, err
+	}
+	if diff.version != "" {
+		err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+			setCondition(status, v1alpha1.ConditionUpgrading, metav1.ConditionTrue, "UpgradeInProgress", fmt.Sprintf("The cluster is being upgraded from %s to %s", cluster.Version, diff.version), kluster.Generation)
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// The cluster is reported as running again by a later pass, e.g. once the upgrade finished
+	return false, nil
 }
 
-// Update the latest status of a kluster
-func (c *controller) updateStatus(id, progress string, kluster *v1alpha1.Kluster) error {
-	// get the latest version of kluster, or there would be error when  fetching the object after it is updated
-	k, err := c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
-	if err != nil {
-		return err
+// Retry for five times if failed to sync deployment, then keep retrying after the requeue interval
+func (c *controller) retry(err error, key interface{}) {
+	if err == nil {
+		// Item is successfully processed.
+		c.queue.Forget(key)
+		return
+	}
+	// If item is not successfully processed,
+	// check how many times you have retried until 5 times
+	if c.queue.NumRequeues(key) < 5 {
+		klog.Infof("Error syncing: %v\n", err)
+		c.queue.AddRateLimited(key)
+		return
 	}
 
-	k.Status.KlusterID = id
-	k.Status.Progress = progress
-	_, err = c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).UpdateStatus(context.Background(), k, metav1.UpdateOptions{})
-	return err
+	// If you have reached the 5 limit times of retry, report the error and look at the item again
+	// after the requeue interval, a kluster that is dropped would not be reconciled until it changes
+	runtime.HandleError(err)
+	klog.Errorf("Retrying %q after %s: %v", key, requeueInterval, err)
+	c.queue.Forget(key)
+	c.queue.AddAfter(key, requeueInterval)
 }
 
-// Add handler: Add obj to queue
+// Add handler: Add key of obj to queue
 func (c *controller) handleAdd(obj interface{}) {
 	klog.Infof("Add called")
-	// Add obj to queue
-	c.queue.Add(obj)
+	c.enqueue(obj)
+}
+
+// Update handler: Add obj to queue when the spec changed or on resync, which checks the cloud cluster for drift
+func (c *controller) handleUpdate(oldObj, newObj interface{}) {
+	oldKluster, ok := oldObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+	newKluster, ok := newObj.(*v1alpha1.Kluster)
+	if !ok {
+		return
+	}
+
+	// Status updates do not change the generation, only spec changes and deletion do.
+	// Resyncs deliver the unchanged object again.
+	deleting := oldKluster.DeletionTimestamp == nil && newKluster.DeletionTimestamp != nil
+	resync := oldKluster.ResourceVersion == newKluster.ResourceVersion
+	if oldKluster.Generation == newKluster.Generation && !deleting && !resync {
+		return
+	}
+
+	klog.Infof("Update called")
+	c.enqueue(newObj)
 }
 
-// Del handler: Add obj to queue
+// Del handler: Add key of obj to queue
 func (c *controller) handleDel(obj interface{}) {
 	klog.Infof("Del called")
-	// Add obj to queue
-	c.queue.Add(obj)
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	// The kluster is gone, its cached provider is not needed anymore
+	if kluster, ok := obj.(*v1alpha1.Kluster); ok {
+		c.providers.Forget(kluster.UID)
+	}
+	c.enqueue(obj)
+}
+
+// Add the namespace/name key of obj to queue, so that events of the same kluster are deduplicated
+func (c *controller) enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	c.queue.Add(key)
 }
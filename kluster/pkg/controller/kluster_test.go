@@ -0,0 +1,285 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	kfake "kluster/pkg/client/clientset/versioned/fake"
+	"kluster/pkg/provider"
+	"kluster/pkg/provider/fake"
+	"kluster/pkg/scope"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const testKey = "team-a/k"
+
+// Queue that records how long keys are delayed instead of delaying them
+type recordingQueue struct {
+	workqueue.RateLimitingInterface
+	after []time.Duration
+}
+
+func (q *recordingQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.after = append(q.after, duration)
+}
+
+// Whether a key was added back to be looked at after the requeue interval
+func (q *recordingQueue) requeued() bool {
+	for _, d := range q.after {
+		if d == requeueInterval {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestKluster() *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a", UID: "uid-1", Generation: 1},
+		Spec: v1alpha1.KlusterSpec{
+			Name:      "k",
+			Region:    "nyc1",
+			Version:   "1.28.2-do.0",
+			Provider:  fake.Name,
+			NodePools: []v1alpha1.NodePool{{Name: "a", Size: "s-1vcpu-2gb", Count: 2}},
+		},
+	}
+}
+
+// Controller whose caches hold the kluster, it calls the fake provider
+func newTestController(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) (*controller, *recordingQueue) {
+	client := kubefake.NewSimpleClientset()
+	klient := kfake.NewSimpleClientset(kluster)
+	s := scope.New(klient, client, nil, "", "", 0)
+	c := NewController(client, klient, s, provider.Registry{fake.Name: f}, Options{})
+	if err := s.Namespaces()[0].Klusters.Informer().GetIndexer().Add(kluster); err != nil {
+		t.Fatalf("adding the kluster to the cache: %v", err)
+	}
+	c.recorder = record.NewFakeRecorder(100)
+	queue := &recordingQueue{RateLimitingInterface: c.queue}
+	c.queue = queue
+	t.Cleanup(queue.ShutDown)
+	return c, queue
+}
+
+// Create the cloud cluster of the kluster in the fake provider
+func createCluster(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) string {
+	id, err := f.Create(context.Background(), kluster.Spec, provider.OwnerTag(kluster.UID))
+	if err != nil {
+		t.Fatalf("creating the cluster: %v", err)
+	}
+	return id
+}
+
+// Let the kluster own a cluster with a recorded ID
+func withCluster(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+	kluster.Status.KlusterID = createCluster(t, f, kluster)
+	kluster.Finalizers = []string{klusterFinalizer}
+}
+
+func deleting(kluster *v1alpha1.Kluster) {
+	now := metav1.Now()
+	kluster.DeletionTimestamp = &now
+	kluster.Finalizers = []string{klusterFinalizer}
+}
+
+// Case of a single syncHandler pass over the kluster of newTestKluster
+type syncTest struct {
+	name     string
+	opts     fake.Options
+	setup    func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster)
+	wantErr  bool
+	requeued bool /* Looked at again after the requeue interval */
+	retried  bool /* Added back rate limited */
+	check    func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster)
+}
+
+func runSyncTests(t *testing.T, tests []syncTest) {
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fake.New(tt.opts)
+			kluster := newTestKluster()
+			if tt.setup != nil {
+				tt.setup(t, f, kluster)
+			}
+			c, queue := newTestController(t, f, kluster)
+
+			err := c.syncHandler(context.Background(), testKey)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("syncHandler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requeued := queue.requeued(); requeued != tt.requeued {
+				t.Errorf("requeued = %v, want %v", requeued, tt.requeued)
+			}
+			if retried := queue.NumRequeues(testKey) > 0; retried != tt.retried {
+				t.Errorf("retried = %v, want %v", retried, tt.retried)
+			}
+
+			latest, err := c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("getting the kluster: %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, f, latest)
+			}
+		})
+	}
+}
+
+func TestSyncHandler(t *testing.T) {
+	tests := []syncTest{
+		{
+			name:     "create",
+			requeued: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !hasFinalizer(kluster) {
+					t.Error("the finalizer was not added")
+				}
+				cluster, err := f.Find(context.Background(), kluster.Spec, provider.OwnerTag(kluster.UID))
+				if err != nil {
+					t.Fatalf("the cluster was not created: %v", err)
+				}
+				if kluster.Status.KlusterID != cluster.ID {
+					t.Errorf("status.klusterID = %q, want %q", kluster.Status.KlusterID, cluster.ID)
+				}
+			},
+		},
+		{
+			name: "error",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				f.InjectFailure(fake.OpCreate, errors.New("quota exceeded"))
+			},
+			wantErr: true,
+			retried: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if kluster.Status.KlusterID != "" {
+					t.Errorf("status.klusterID = %q, want none", kluster.Status.KlusterID)
+				}
+			},
+		},
+	}
+
+	runSyncTests(t, tests)
+}
+
+func TestRequeue(t *testing.T) {
+	tests := []syncTest{
+		{
+			name:     "requeue while the cluster is provisioning",
+			opts:     fake.Options{ProvisioningDelay: time.Hour},
+			setup:    withCluster,
+			requeued: true,
+		},
+		{
+			name: "requeue while the cluster is upgrading",
+			opts: fake.Options{UpgradeDelay: time.Hour},
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				kluster.Spec.Version = "1.27.6-do.0"
+				withCluster(t, f, kluster)
+				kluster.Spec.Version = "1.28.2-do.0"
+				if err := f.Upgrade(context.Background(), kluster.Status.KlusterID, "1.28.2-do.0"); err != nil {
+					t.Fatalf("upgrading the cluster: %v", err)
+				}
+			},
+			requeued: true,
+		},
+	}
+
+	runSyncTests(t, tests)
+}
+
+func TestRetry(t *testing.T) {
+	c, queue := newTestController(t, fake.New(fake.Options{}), newTestKluster())
+	failed := errors.New("failed")
+
+	for i := 1; i <= 5; i++ {
+		c.retry(failed, testKey)
+		if n := queue.NumRequeues(testKey); n != i {
+			t.Fatalf("retry %d: NumRequeues() = %d, want %d", i, n, i)
+		}
+		if queue.requeued() {
+			t.Fatalf("retry %d: requeued after the requeue interval before the retries were used up", i)
+		}
+	}
+
+	// The kluster is not dropped once the rate limited retries are used up
+	c.retry(failed, testKey)
+	if !queue.requeued() {
+		t.Error("the kluster was not requeued after the rate limited retries were used up")
+	}
+	if n := queue.NumRequeues(testKey); n != 0 {
+		t.Errorf("NumRequeues() = %d after the requeue, want the backoff to start over", n)
+	}
+
+	c.retry(failed, testKey)
+	c.retry(nil, testKey)
+	if n := queue.NumRequeues(testKey); n != 0 {
+		t.Errorf("NumRequeues() = %d after a successful sync, want 0", n)
+	}
+}
+
+func TestCreateOrAdopt(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "create",
+			want: "fake-1",
+		},
+		{
+			name:  "adopt the cluster of an earlier create",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) { createCluster(t, f, kluster) },
+			want:  "fake-1",
+		},
+		{
+			name: "do not adopt the cluster of another kluster",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				other := newTestKluster()
+				other.UID = "uid-2"
+				createCluster(t, f, other)
+			},
+			want: "fake-2",
+		},
+		{
+			name: "do not create when the lookup fails",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				f.InjectFailure(fake.OpFind, errors.New("unavailable"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fake.New(fake.Options{})
+			kluster := newTestKluster()
+			if tt.setup != nil {
+				tt.setup(t, f, kluster)
+			}
+			c, _ := newTestController(t, f, kluster)
+
+			id, err := c.createOrAdopt(context.Background(), f, kluster)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("createOrAdopt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if id != tt.want {
+				t.Errorf("createOrAdopt() = %q, want %q", id, tt.want)
+			}
+			if tt.wantErr {
+				if _, err := f.Find(context.Background(), kluster.Spec, provider.OwnerTag(kluster.UID)); !errors.Is(err, provider.ErrNotFound) {
+					t.Errorf("a cluster was created although the lookup failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Key of the kubeconfig in the data of the kubeconfig secret
+const kubeConfigKey = "kubeconfig"
+
+// How long before the credentials expire the kubeconfig is fetched again
+const kubeConfigRefreshBefore = 24 * time.Hour
+
+// Annotation of the kubeconfig secret with the time it has to be refreshed at
+const refreshAfterAnnotation = "siqi.dev/refresh-after"
+
+// Name of the secret that holds the kubeconfig of the kluster's cloud cluster
+func kubeConfigSecretName(kluster *v1alpha1.Kluster) string {
+	return kluster.Name + "-kubeconfig"
+}
+
+// Write the kubeconfig of the running cluster into a secret owned by the kluster
+// and schedule the next pass before its credentials expire
+func (c *controller) syncKubeConfig(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster, id string) error {
+	name := kubeConfigSecretName(kluster)
+	secret, err := c.client.CoreV1().Secrets(kluster.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("getting secret %s: %w", name, err)
+	}
+	if err != nil {
+		secret = nil
+	}
+	// Never overwrite a secret that someone else created with the same name
+	if secret != nil && !metav1.IsControlledBy(secret, kluster) {
+		c.recorder.Eventf(kluster, corev1.EventTypeWarning, "KubeConfigConflict", "Secret %s exists and is not owned by the kluster", name)
+		return fmt.Errorf("secret %s exists and is not owned by kluster %s", name, kluster.Name)
+	}
+
+	if secret != nil && len(secret.Data[kubeConfigKey]) > 0 {
+		refreshAt, err := time.Parse(time.RFC3339, secret.Annotations[refreshAfterAnnotation])
+		if err == nil && time.Now().Before(refreshAt) {
+			c.scheduleKubeConfigRefresh(kluster, refreshAt)
+			return nil
+		}
+	}
+
+	config, err := p.KubeConfig(ctx, id)
+	if err != nil {
+		return fmt.Errorf("fetching the kubeconfig of cluster %s: %w", id, err)
+	}
+	refreshAt := kubeConfigRefreshTime(time.Now(), config.ExpiresAt)
+	annotations := map[string]string{}
+	if !refreshAt.IsZero() {
+		annotations[refreshAfterAnnotation] = refreshAt.UTC().Format(time.RFC3339)
+	}
+
+	if secret == nil {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   kluster.Namespace,
+				Annotations: annotations,
+				// The secret is garbage collected together with the kluster
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(kluster, v1alpha1.SchemeGroupVersion.WithKind("Kluster")),
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{kubeConfigKey: config.Data},
+		}
+		_, err = c.client.CoreV1().Secrets(kluster.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret = secret.DeepCopy()
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		delete(secret.Annotations, refreshAfterAnnotation)
+		for k, v := range annotations {
+			secret.Annotations[k] = v
+		}
+		secret.Data = map[string][]byte{kubeConfigKey: config.Data}
+		_, err = c.client.CoreV1().Secrets(kluster.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("writing secret %s: %w", name, err)
+	}
+	klog.Infof("kubeconfig of kluster %s was written to secret %s\n", kluster.Name, name)
+	c.recorder.Eventf(kluster, corev1.EventTypeNormal, "KubeConfigUpdated", "Kubeconfig was written to secret %s", name)
+
+	err = c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		status.KubeConfigSecret = name
+		status.KubeConfigExpiresAt = nil
+		if !config.ExpiresAt.IsZero() {
+			status.KubeConfigExpiresAt = &metav1.Time{Time: config.ExpiresAt}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if !refreshAt.IsZero() {
+		c.scheduleKubeConfigRefresh(kluster, refreshAt)
+	}
+	return nil
+}
+
+// Time to fetch a kubeconfig again, credentials that live shorter than twice the refresh
+// margin are refreshed halfway through their lifetime. Credentials that do not expire are never refreshed.
+func kubeConfigRefreshTime(now, expiresAt time.Time) time.Time {
+	if expiresAt.IsZero() {
+		return time.Time{}
+	}
+	before := kubeConfigRefreshBefore
+	if lifetime := expiresAt.Sub(now); lifetime < 2*before {
+		before = lifetime / 2
+	}
+	return expiresAt.Add(-before)
+}
+
+// Requeue the kluster in time to refresh its kubeconfig
+func (c *controller) scheduleKubeConfigRefresh(kluster *v1alpha1.Kluster, refreshAt time.Time) {
+	key, err := cache.MetaNamespaceKeyFunc(kluster)
+	if err != nil {
+		return
+	}
+	// Credentials that are already expired must not make the kluster spin in the queue
+	delay := time.Until(refreshAt)
+	if delay < requeueInterval {
+		delay = requeueInterval
+	}
+	c.queue.AddAfter(key, delay)
+}
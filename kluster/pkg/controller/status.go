@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Update the latest status of a kluster with the changes made by mutate
+func (c *controller) updateStatus(kluster *v1alpha1.Kluster, mutate func(status *v1alpha1.KlsuterStatus)) error {
+	// get the latest version of kluster, or there would be error when  fetching the object after it is updated
+	k, err := c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).Get(context.Background(), kluster.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	mutate(&k.Status)
+	_, err = c.klient.SiqiV1alpha1().Klusters(kluster.Namespace).UpdateStatus(context.Background(), k, metav1.UpdateOptions{})
+	return err
+}
+
+// Record a failed reconcile in the status, failing to do so is only logged
+func (c *controller) recordError(ctx context.Context, kluster *v1alpha1.Kluster, reconcileErr error) {
+	// Calls cancelled by the shutdown did not fail because of the kluster, the next run picks it up again
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return
+	}
+	err := c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		now := metav1.Now()
+		status.LastError = reconcileErr.Error()
+		status.LastErrorTime = &now
+		if errors.Is(reconcileErr, provider.ErrInvalidCredentials) {
+			reason := "InvalidCredentials"
+			if errors.Is(reconcileErr, errSecretNotWatched) {
+				reason = secretNotWatched
+			}
+			setCondition(status, v1alpha1.ConditionCredentialsValid, metav1.ConditionFalse, reason, reconcileErr.Error(), kluster.Generation)
+		}
+	})
+	if err != nil && !apierrors.IsNotFound(err) {
+		klog.Errorf("error %s, recording the error in the status of kluster %s\n", err.Error(), kluster.Name)
+	}
+}
+
+// Record a successful reconcile of the given generation of the spec
+func markReconciled(status *v1alpha1.KlsuterStatus, generation int64) {
+	now := metav1.Now()
+	status.ObservedGeneration = generation
+	status.LastReconcileTime = &now
+	status.LastError = ""
+	status.LastErrorTime = nil
+	acceptCredentials(status, generation)
+}
+
+// Record that the provider accepted the credentials of the kluster
+func acceptCredentials(status *v1alpha1.KlsuterStatus, generation int64) {
+	setCondition(status, v1alpha1.ConditionCredentialsValid, metav1.ConditionTrue, "Accepted", "The provider accepted the credentials", generation)
+}
+
+// Record the state of the cloud cluster and its node pools in the status conditions
+func observeCluster(status *v1alpha1.KlsuterStatus, cluster *provider.Cluster, generation int64) {
+	status.NodePools = nodePoolStatus(cluster)
+
+	ready := metav1.ConditionFalse
+	provisioning := metav1.ConditionFalse
+	degraded := metav1.ConditionFalse
+	reason := "Cluster" + stateReason(cluster.State)
+	message := "The cluster is " + cluster.State
+
+	switch cluster.State {
+	case provider.StateRunning:
+		ready = metav1.ConditionTrue
+	case provider.StateProvisioning:
+		provisioning = metav1.ConditionTrue
+	case provider.StateDegraded, provider.StateError:
+		degraded = metav1.ConditionTrue
+	case provider.StateUpgrading:
+		// Completion of the upgrade is reported by the reconcile that finds the cluster running again
+		setCondition(status, v1alpha1.ConditionUpgrading, metav1.ConditionTrue, reason, message, generation)
+	}
+
+	setCondition(status, v1alpha1.ConditionReady, ready, reason, message, generation)
+	setCondition(status, v1alpha1.ConditionProvisioning, provisioning, reason, message, generation)
+	setCondition(status, v1alpha1.ConditionDegraded, degraded, reason, message, generation)
+}
+
+// Turn a provider state into a CamelCase condition reason
+func stateReason(state string) string {
+	if state == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(state[:1]) + state[1:]
+}
+
+func setCondition(status *v1alpha1.KlsuterStatus, conditionType string, value metav1.ConditionStatus, reason, message string, generation int64) {
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             value,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+}
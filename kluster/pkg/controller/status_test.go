@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+	"kluster/pkg/provider/fake"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestObserveCluster(t *testing.T) {
+	tests := []struct {
+		state string
+		want  map[string]metav1.ConditionStatus
+	}{
+		{
+			state: provider.StateRunning,
+			want:  map[string]metav1.ConditionStatus{v1alpha1.ConditionReady: metav1.ConditionTrue, v1alpha1.ConditionProvisioning: metav1.ConditionFalse, v1alpha1.ConditionDegraded: metav1.ConditionFalse},
+		},
+		{
+			state: provider.StateProvisioning,
+			want:  map[string]metav1.ConditionStatus{v1alpha1.ConditionReady: metav1.ConditionFalse, v1alpha1.ConditionProvisioning: metav1.ConditionTrue, v1alpha1.ConditionDegraded: metav1.ConditionFalse},
+		},
+		{
+			state: provider.StateDegraded,
+			want:  map[string]metav1.ConditionStatus{v1alpha1.ConditionReady: metav1.ConditionFalse, v1alpha1.ConditionProvisioning: metav1.ConditionFalse, v1alpha1.ConditionDegraded: metav1.ConditionTrue},
+		},
+		{
+			state: provider.StateUpgrading,
+			want:  map[string]metav1.ConditionStatus{v1alpha1.ConditionReady: metav1.ConditionFalse, v1alpha1.ConditionUpgrading: metav1.ConditionTrue},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			status := &v1alpha1.KlsuterStatus{}
+			cluster := &provider.Cluster{State: tt.state, NodePools: []provider.NodePool{{Name: "a", Count: 2, Ready: 1}}}
+			observeCluster(status, cluster, 3)
+
+			for conditionType, want := range tt.want {
+				cond := meta.FindStatusCondition(status.Conditions, conditionType)
+				if cond == nil || cond.Status != want {
+					t.Errorf("%s condition = %+v, want %s", conditionType, cond, want)
+					continue
+				}
+				if cond.ObservedGeneration != 3 {
+					t.Errorf("%s condition observedGeneration = %d, want 3", conditionType, cond.ObservedGeneration)
+				}
+				if wantReason := "Cluster" + stateReason(tt.state); cond.Reason != wantReason {
+					t.Errorf("%s condition reason = %q, want %q", conditionType, cond.Reason, wantReason)
+				}
+			}
+			if len(status.NodePools) != 1 || status.NodePools[0].Name != "a" {
+				t.Errorf("status.nodePools = %+v, want pool a", status.NodePools)
+			}
+		})
+	}
+}
+
+func TestMarkReconciled(t *testing.T) {
+	errorTime := metav1.Now()
+	status := &v1alpha1.KlsuterStatus{LastError: "failed", LastErrorTime: &errorTime}
+	markReconciled(status, 2)
+
+	if status.ObservedGeneration != 2 {
+		t.Errorf("observedGeneration = %d, want 2", status.ObservedGeneration)
+	}
+	if status.LastReconcileTime == nil {
+		t.Error("lastReconcileTime was not set")
+	}
+	if status.LastError != "" || status.LastErrorTime != nil {
+		t.Errorf("lastError = %q at %v, want it cleared", status.LastError, status.LastErrorTime)
+	}
+	if !meta.IsStatusConditionTrue(status.Conditions, v1alpha1.ConditionCredentialsValid) {
+		t.Errorf("conditions = %+v, want CredentialsValid true", status.Conditions)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	tests := []syncTest{
+		{
+			name:  "running cluster that matches the spec",
+			setup: withCluster,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionReady) {
+					t.Errorf("conditions = %+v, want Ready true", kluster.Status.Conditions)
+				}
+				if kluster.Status.ObservedGeneration != kluster.Generation {
+					t.Errorf("status.observedGeneration = %d, want %d", kluster.Status.ObservedGeneration, kluster.Generation)
+				}
+				if kluster.Status.KubeConfigSecret != "k-kubeconfig" {
+					t.Errorf("status.kubeConfigSecret = %q, want k-kubeconfig", kluster.Status.KubeConfigSecret)
+				}
+			},
+		},
+		{
+			name:     "provisioning cluster",
+			opts:     fake.Options{ProvisioningDelay: time.Hour},
+			setup:    withCluster,
+			requeued: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !meta.IsStatusConditionTrue(kluster.Status.Conditions, v1alpha1.ConditionProvisioning) {
+					t.Errorf("conditions = %+v, want Provisioning true", kluster.Status.Conditions)
+				}
+				if kluster.Status.ObservedGeneration != 0 {
+					t.Errorf("status.observedGeneration = %d, want none before the cluster runs", kluster.Status.ObservedGeneration)
+				}
+			},
+		},
+		{
+			name: "error",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				f.InjectFailure(fake.OpCreate, errors.New("quota exceeded"))
+			},
+			wantErr: true,
+			retried: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				if !strings.Contains(kluster.Status.LastError, "quota exceeded") {
+					t.Errorf("status.lastError = %q, want the error of the provider", kluster.Status.LastError)
+				}
+				if kluster.Status.LastErrorTime == nil {
+					t.Error("status.lastErrorTime was not set")
+				}
+			},
+		},
+		{
+			name: "invalid credentials",
+			setup: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				f.InjectFailure(fake.OpFind, fmt.Errorf("listing clusters: %w", provider.ErrInvalidCredentials))
+			},
+			wantErr: true,
+			retried: true,
+			check: func(t *testing.T, f *fake.Provider, kluster *v1alpha1.Kluster) {
+				cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionCredentialsValid)
+				if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "InvalidCredentials" {
+					t.Errorf("CredentialsValid condition = %+v, want false with reason InvalidCredentials", cond)
+				}
+			},
+		},
+	}
+
+	runSyncTests(t, tests)
+}
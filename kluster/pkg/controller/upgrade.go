@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Check that the cluster can be upgraded to the version of the spec. Downgrades and versions the
+// provider does not offer are refused with an event and the Upgrading condition, they are not
+// retried until the spec changes. A version that differs from a reconciled spec is reported as drift before.
+func (c *controller) validateUpgrade(ctx context.Context, p provider.Provider, kluster *v1alpha1.Kluster, cluster *provider.Cluster, target string) (bool, error) {
+	cmp, err := provider.CompareVersions(target, cluster.Version)
+	if err != nil {
+		return false, c.refuseUpgrade(kluster, "InvalidVersion", fmt.Sprintf("Version %s can not be parsed: %s", target, err.Error()))
+	}
+	if cmp < 0 {
+		return false, c.refuseUpgrade(kluster, "DowngradeRefused", fmt.Sprintf("Cluster runs %s, downgrading to %s is not supported", cluster.Version, target))
+	}
+
+	upgrades, err := p.Upgrades(ctx, cluster.ID)
+	if err != nil {
+		return false, fmt.Errorf("listing upgrades of cluster %s: %w", cluster.ID, err)
+	}
+	for _, v := range upgrades {
+		if v == target {
+			return true, nil
+		}
+	}
+
+	available := "none"
+	if len(upgrades) > 0 {
+		available = strings.Join(upgrades, ", ")
+	}
+	return false, c.refuseUpgrade(kluster, "VersionUnavailable", fmt.Sprintf("Cluster runs %s and can not be upgraded to %s, available upgrades: %s", cluster.Version, target, available))
+}
+
+// Report an upgrade that will not be made
+func (c *controller) refuseUpgrade(kluster *v1alpha1.Kluster, reason, message string) error {
+	// The refusal of this generation was reported before
+	cond := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionUpgrading)
+	if cond != nil && cond.Reason == reason && cond.ObservedGeneration == kluster.Generation {
+		return nil
+	}
+
+	c.recorder.Event(kluster, corev1.EventTypeWarning, reason, message)
+	return c.updateStatus(kluster, func(status *v1alpha1.KlsuterStatus) {
+		setCondition(status, v1alpha1.ConditionUpgrading, metav1.ConditionFalse, reason, message, kluster.Generation)
+	})
+}
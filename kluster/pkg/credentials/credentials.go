@@ -0,0 +1,118 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"kluster/pkg/provider"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// DefaultKey is the key of the token in secrets that do not name another one
+const DefaultKey = "token"
+
+// Source is where the provider token of a kluster is read from
+type Source interface {
+	// Credentials reads the token, it fails with provider.ErrInvalidCredentials when there is none
+	Credentials() (provider.Credentials, error)
+	// String describes the source in events and errors
+	String() string
+}
+
+// Secret reads the token from a key of a secret in the informer cache
+type Secret struct {
+	Lister    corelisters.SecretLister   /* Cache of the secrets */
+	Client    corev1client.SecretsGetter /* Reads the secrets missing from the cache, e.g. those outside of the secret selector, nil only uses the cache */
+	Namespace string                     /* Namespace of the secret */
+	Name      string                     /* Name of the secret */
+	Key       string                     /* Key of the token in the secret, empty is DefaultKey */
+}
+
+func (s *Secret) Credentials() (provider.Credentials, error) {
+	secret, err := s.Lister.Secrets(s.Namespace).Get(s.Name)
+	if apierrors.IsNotFound(err) && s.Client != nil {
+		secret, err = s.Client.Secrets(s.Namespace).Get(context.Background(), s.Name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return provider.Credentials{}, fmt.Errorf("%w: getting secret %s/%s: %v", provider.ErrInvalidCredentials, s.Namespace, s.Name, err)
+	}
+	token := strings.TrimSpace(string(secret.Data[s.key()]))
+	if token == "" {
+		return provider.Credentials{}, fmt.Errorf("%w: secret %s/%s has no %s", provider.ErrInvalidCredentials, s.Namespace, s.Name, s.key())
+	}
+	return provider.Credentials{
+		Token:           token,
+		SecretUID:       secret.UID,
+		ResourceVersion: secret.ResourceVersion,
+	}, nil
+}
+
+func (s *Secret) String() string {
+	return fmt.Sprintf("secret %s/%s key %s", s.Namespace, s.Name, s.key())
+}
+
+func (s *Secret) key() string {
+	if s.Key == "" {
+		return DefaultKey
+	}
+	return s.Key
+}
+
+// File reads the token from a file of the controller pod, like the ones a CSI secret store mounts.
+// The file is read on every reconcile, so rotations are picked up with the next one.
+type File struct {
+	Path string /* Path of the file with the token */
+}
+
+func (f *File) Credentials() (provider.Credentials, error) {
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return provider.Credentials{}, fmt.Errorf("%w: reading token file: %v", provider.ErrInvalidCredentials, err)
+	}
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return provider.Credentials{}, fmt.Errorf("%w: reading token file: %v", provider.ErrInvalidCredentials, err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return provider.Credentials{}, fmt.Errorf("%w: token file %s is empty", provider.ErrInvalidCredentials, f.Path)
+	}
+	// The modification time tells the cache when the file was rotated
+	return provider.Credentials{
+		Token:           token,
+		SecretUID:       types.UID("file:" + f.Path),
+		ResourceVersion: strconv.FormatInt(info.ModTime().UnixNano(), 10) + "/" + strconv.FormatInt(info.Size(), 10),
+	}, nil
+}
+
+func (f *File) String() string {
+	return "file " + f.Path
+}
+
+// Env reads the token from an environment variable of the controller, which does not change while it runs
+type Env struct {
+	Name string /* Name of the environment variable */
+}
+
+func (e *Env) Credentials() (provider.Credentials, error) {
+	token := strings.TrimSpace(os.Getenv(e.Name))
+	if token == "" {
+		return provider.Credentials{}, fmt.Errorf("%w: environment variable %s is not set", provider.ErrInvalidCredentials, e.Name)
+	}
+	return provider.Credentials{
+		Token:     token,
+		SecretUID: types.UID("env:" + e.Name),
+	}, nil
+}
+
+func (e *Env) String() string {
+	return "environment variable " + e.Name
+}
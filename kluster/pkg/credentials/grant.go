@@ -0,0 +1,52 @@
+package credentials
+
+import (
+	"fmt"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	klister "kluster/pkg/client/listers/siqi.dev/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SecretReferenceAllowed reports whether klusters of the namespace from may read their token from the secret
+// namespace/name. The secrets of their own namespace are always allowed, the ones of other namespaces
+// need a KlusterReferenceGrant in the namespace of the secret that lists from.
+func SecretReferenceAllowed(grants klister.KlusterReferenceGrantLister, from, namespace, name string) (bool, error) {
+	if from == namespace {
+		return true, nil
+	}
+	list, err := grants.KlusterReferenceGrants(namespace).List(labels.Everything())
+	if err != nil {
+		return false, fmt.Errorf("listing the KlusterReferenceGrants of namespace %s: %w", namespace, err)
+	}
+	for _, grant := range list {
+		if grantsFrom(grant.Spec.From, from) && grantsTo(grant.Spec.To, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Whether the namespace is listed in from
+func grantsFrom(from []v1alpha1.ReferenceGrantFrom, namespace string) bool {
+	for _, f := range from {
+		if f.Namespace == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// An empty list grants every secret of the namespace
+func grantsTo(to []v1alpha1.ReferenceGrantTo, name string) bool {
+	if len(to) == 0 {
+		return true
+	}
+	for _, t := range to {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
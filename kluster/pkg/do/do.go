@@ -2,78 +2,283 @@ package do
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"strings"
+	"net/http"
+	"net/url"
+	"time"
 
 	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
 
 	"github.com/digitalocean/godo"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
 )
 
-var Token string
+// Name of the digital ocean provider in spec.provider
+const Name = "digitalocean"
+
+// How long the credentials of the kubeconfigs are valid, the longest DO allows
+const kubeConfigExpiry = 7 * 24 * time.Hour
+
+// Factory builds digital ocean providers that call the API with the token of a kluster
+type Factory struct {
+	baseURL string /* Override of the DO API URL, e.g. to use a doserver in tests */
+}
+
+var _ provider.Factory = &Factory{}
+
+// Create a new digital ocean provider factory, an empty baseURL uses the public DO API
+func New(baseURL string) *Factory {
+	return &Factory{baseURL: baseURL}
+}
+
+// For returns a provider that makes its calls with the token of creds
+func (f *Factory) For(creds provider.Credentials) (provider.Provider, error) {
+	if creds.Token == "" {
+		return nil, fmt.Errorf("%w: no digital ocean token", provider.ErrInvalidCredentials)
+	}
+	client := godo.NewFromToken(creds.Token)
+	if f.baseURL != "" {
+		u, err := url.Parse(f.baseURL)
+		if err != nil {
+			return nil, err
+		}
+		client.BaseURL = u
+	}
+	return &Provider{client: client}, nil
+}
+
+// Provider creates and manages clusters in digital ocean with the token of one kluster
+type Provider struct {
+	client *godo.Client /* Client authenticated with the token */
+}
+
+var _ provider.Provider = &Provider{}
 
 // Create digital ocean cluster
-func Create(c kubernetes.Interface, spec v1alpha1.KlusterSpec) (string, error) {
-	token, err := getToken(c, spec.TokenSecret)
+func (p *Provider) Create(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (string, error) {
+	if len(spec.NodePools) == 0 {
+		return "", fmt.Errorf("kluster %s has no node pools", spec.Name)
+	}
+
+	cluster, _, err := p.client.Kubernetes.Create(ctx, createRequest(spec, owner))
 	if err != nil {
-		return "", err
+		return "", wrapError(err)
 	}
-	Token = token
 
-	client := godo.NewFromToken(token)
-	fmt.Println(client)
+	return cluster.ID, nil
+}
+
+// Build the DO create request from the kluster spec
+func createRequest(spec v1alpha1.KlusterSpec, owner string) *godo.KubernetesClusterCreateRequest {
 	request := &godo.KubernetesClusterCreateRequest{
 		Name:        spec.Name,
 		RegionSlug:  spec.Region,
 		VersionSlug: spec.Version,
-		NodePools: []*godo.KubernetesNodePoolCreateRequest{
-			&godo.KubernetesNodePoolCreateRequest{
-				Size:  spec.NodePools[0].Size,
-				Name:  spec.NodePools[0].Name,
-				Count: spec.NodePools[0].Count,
-			},
-		},
+		Tags:        []string{owner},
+	}
+	for _, np := range spec.NodePools {
+		request.NodePools = append(request.NodePools, nodePoolRequest(np))
+	}
+	return request
+}
+
+// Build the DO node pool create request from a kluster node pool
+func nodePoolRequest(np v1alpha1.NodePool) *godo.KubernetesNodePoolCreateRequest {
+	return &godo.KubernetesNodePoolCreateRequest{
+		Size:      np.Size,
+		Name:      np.Name,
+		Count:     np.Count,
+		AutoScale: np.AutoScale,
+		MinNodes:  np.MinNodes,
+		MaxNodes:  np.MaxNodes,
 	}
+}
 
-	cluster, _, err := client.Kubernetes.Create(context.Background(), request)
+// Get digital ocean cluster and its node pools
+func (p *Provider) Get(ctx context.Context, id string) (*provider.Cluster, error) {
+	cluster, _, err := p.client.Kubernetes.Get(ctx, id)
 	if err != nil {
-		return "", err
+		return nil, wrapError(err)
 	}
+	return toCluster(cluster), nil
+}
 
-	return cluster.ID, nil
+// Find the digital ocean cluster with the name and the owner tag
+func (p *Provider) Find(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (*provider.Cluster, error) {
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		clusters, resp, err := p.client.Kubernetes.List(ctx, opt)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		for _, cluster := range clusters {
+			c := toCluster(cluster)
+			if c.Name == spec.Name && c.HasTag(owner) {
+				return c, nil
+			}
+		}
+		if resp == nil || resp.Links == nil || resp.Links.IsLastPage() {
+			return nil, provider.ErrNotFound
+		}
+		opt.Page++
+	}
+}
+
+// Convert the godo cluster to the provider view of it
+func toCluster(cluster *godo.KubernetesCluster) *provider.Cluster {
+	c := &provider.Cluster{
+		ID:      cluster.ID,
+		Name:    cluster.Name,
+		Region:  cluster.RegionSlug,
+		Version: cluster.VersionSlug,
+		Tags:    cluster.Tags,
+	}
+	if cluster.Status != nil {
+		c.State = string(cluster.Status.State)
+	}
+	for _, np := range cluster.NodePools {
+		ready := 0
+		for _, node := range np.Nodes {
+			if node.Status != nil && node.Status.State == "running" {
+				ready++
+			}
+		}
+		c.NodePools = append(c.NodePools, provider.NodePool{
+			ID:        np.ID,
+			Name:      np.Name,
+			Size:      np.Size,
+			Count:     np.Count,
+			Ready:     ready,
+			AutoScale: np.AutoScale,
+			MinNodes:  np.MinNodes,
+			MaxNodes:  np.MaxNodes,
+		})
+	}
+	return c
 }
 
-// Get digital ocean cluster status
-func ClusterState(id string) (string, error) {
-	client := godo.NewFromToken(Token)
-	cluster, _, err := client.Kubernetes.Get(context.Background(), id)
+// Delete digital ocean cluster
+func (p *Provider) Delete(ctx context.Context, id string) error {
+	_, err := p.client.Kubernetes.Delete(ctx, id)
 	if err != nil {
-		return "", err
+		return wrapError(err)
 	}
-	return string(cluster.Status.State), err
+
+	return nil
 }
 
-// Get token from secretes of existing clusters
-func getToken(client kubernetes.Interface, sec string) (string, error) {
-	namespace := strings.Split(sec, "/")[0]
-	name := strings.Split(sec, "/")[1]
-	s, err := client.CoreV1().Secrets(namespace).Get(context.Background(), name, metav1.GetOptions{})
+// Scale the node pool with the same name to the count in pool
+func (p *Provider) Scale(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	np, err := findNodePool(ctx, p.client, id, pool.Name)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	return string(s.Data["token"]), nil
+	request := &godo.KubernetesNodePoolUpdateRequest{
+		Name:      np.Name,
+		AutoScale: &pool.AutoScale,
+		MinNodes:  &pool.MinNodes,
+		MaxNodes:  &pool.MaxNodes,
+	}
+	// The provider decides the size of pools that scale automatically
+	if !pool.AutoScale {
+		request.Count = &pool.Count
+	}
+	_, _, err = p.client.Kubernetes.UpdateNodePool(ctx, id, np.ID, request)
+	return wrapError(err)
 }
 
-// Delete digital ocean cluster
-func Delete(id string) error {
-	client := godo.NewFromToken(Token)
-	_, err := client.Kubernetes.Delete(context.TODO(), id)
+// Add a node pool to digital ocean cluster
+func (p *Provider) CreateNodePool(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	_, _, err := p.client.Kubernetes.CreateNodePool(ctx, id, nodePoolRequest(pool))
+	return wrapError(err)
+}
+
+// Delete the node pool with the name from digital ocean cluster
+func (p *Provider) DeleteNodePool(ctx context.Context, id, name string) error {
+	np, err := findNodePool(ctx, p.client, id, name)
 	if err != nil {
 		return err
 	}
+	_, err = p.client.Kubernetes.DeleteNodePool(ctx, id, np.ID)
+	return wrapError(err)
+}
 
-	return nil
+// Upgrade digital ocean cluster to the version slug
+func (p *Provider) Upgrade(ctx context.Context, id, version string) error {
+	_, err := p.client.Kubernetes.Upgrade(ctx, id, &godo.KubernetesClusterUpgradeRequest{
+		VersionSlug: version,
+	})
+	return wrapError(err)
+}
+
+// List the version slugs digital ocean cluster can be upgraded to
+func (p *Provider) Upgrades(ctx context.Context, id string) ([]string, error) {
+	upgrades, _, err := p.client.Kubernetes.GetUpgrades(ctx, id)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	versions := []string{}
+	for _, v := range upgrades {
+		versions = append(versions, v.Slug)
+	}
+	return versions, nil
+}
+
+// Get the kubeconfig of digital ocean cluster, the credentials in it are short-lived.
+// The expiry is requested with the kubeconfig, so that it is the one of the token in it.
+func (p *Provider) KubeConfig(ctx context.Context, id string) (*provider.KubeConfig, error) {
+	requested := time.Now()
+	config, _, err := p.client.Kubernetes.GetKubeConfigWithExpiry(ctx, id, int64(kubeConfigExpiry.Seconds()))
+	if err != nil {
+		return nil, wrapError(err)
+	}
+
+	return &provider.KubeConfig{
+		Data:      config.KubeconfigYAML,
+		ExpiresAt: requested.Add(kubeConfigExpiry),
+	}, nil
+}
+
+// Find the node pool of a cluster by its name
+func findNodePool(ctx context.Context, client *godo.Client, id, name string) (*godo.KubernetesNodePool, error) {
+	opt := &godo.ListOptions{Page: 1, PerPage: 200}
+	for {
+		pools, resp, err := client.Kubernetes.ListNodePools(ctx, id, opt)
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		for _, np := range pools {
+			if np.Name == name {
+				return np, nil
+			}
+		}
+		// godo does not return the links of node pool lists, a page that is not full is the last one
+		lastPage := len(pools) < opt.PerPage
+		if resp != nil && resp.Links != nil {
+			lastPage = resp.Links.IsLastPage()
+		}
+		if lastPage {
+			return nil, fmt.Errorf("node pool %s not found in cluster %s", name, id)
+		}
+		opt.Page++
+	}
+}
+
+// Translate godo errors to provider errors
+func wrapError(err error) error {
+	var errResp *godo.ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response == nil {
+		return err
+	}
+	code := errResp.Response.StatusCode
+	switch code {
+	case http.StatusNotFound:
+		err = provider.ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		err = fmt.Errorf("%w: %s", provider.ErrInvalidCredentials, errResp.Message)
+	}
+	return &provider.APIError{StatusCode: code, Err: err}
 }
@@ -0,0 +1,225 @@
+package do
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/do/doserver"
+	"kluster/pkg/provider"
+
+	"github.com/digitalocean/godo"
+)
+
+// ID the doserver gives the first cluster and the path of its node pools
+const (
+	clusterID = "00000001-0000-4000-8000-000000000000"
+	poolsPath = "/v2/kubernetes/clusters/" + clusterID + "/node_pools"
+)
+
+func TestProviderRequests(t *testing.T) {
+	owner := provider.OwnerTag("uid")
+	spec := v1alpha1.KlusterSpec{
+		Name:    "k",
+		Region:  "nyc1",
+		Version: "1.27.6-do.0",
+		NodePools: []v1alpha1.NodePool{
+			{Name: "a", Size: "s-1vcpu-2gb", Count: 2},
+			{Name: "b", Size: "s-2vcpu-4gb", Count: 1, AutoScale: true, MinNodes: 1, MaxNodes: 3},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		call   func(ctx context.Context, p provider.Provider) error /* Called after the cluster of spec was created, nil checks the create request */
+		method string
+		path   string
+		query  url.Values  /* Expected query, nil skips the check */
+		body   interface{} /* Expected payload, nil skips the check */
+		check  func(t *testing.T, s *doserver.Server)
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/v2/kubernetes/clusters",
+			body: &godo.KubernetesClusterCreateRequest{
+				Name:        "k",
+				RegionSlug:  "nyc1",
+				VersionSlug: "1.27.6-do.0",
+				Tags:        []string{owner},
+				NodePools: []*godo.KubernetesNodePoolCreateRequest{
+					{Name: "a", Size: "s-1vcpu-2gb", Count: 2},
+					{Name: "b", Size: "s-2vcpu-4gb", Count: 1, AutoScale: true, MinNodes: 1, MaxNodes: 3},
+				},
+			},
+		},
+		{
+			name: "scale a fixed pool",
+			call: func(ctx context.Context, p provider.Provider) error {
+				return p.Scale(ctx, clusterID, v1alpha1.NodePool{Name: "a", Size: "s-1vcpu-2gb", Count: 4})
+			},
+			method: http.MethodPut,
+			path:   poolsPath + "/00000001-pool-0",
+			body: &godo.KubernetesNodePoolUpdateRequest{
+				Name:      "a",
+				Count:     godo.Int(4),
+				AutoScale: godo.Bool(false),
+				MinNodes:  godo.Int(0),
+				MaxNodes:  godo.Int(0),
+			},
+		},
+		{
+			name: "update the range of an autoscaled pool",
+			call: func(ctx context.Context, p provider.Provider) error {
+				return p.Scale(ctx, clusterID, v1alpha1.NodePool{Name: "b", Size: "s-2vcpu-4gb", Count: 1, AutoScale: true, MinNodes: 2, MaxNodes: 5})
+			},
+			method: http.MethodPut,
+			path:   poolsPath + "/00000001-pool-1",
+			body: &godo.KubernetesNodePoolUpdateRequest{
+				Name:      "b",
+				AutoScale: godo.Bool(true),
+				MinNodes:  godo.Int(2),
+				MaxNodes:  godo.Int(5),
+			},
+		},
+		{
+			name: "create a node pool",
+			call: func(ctx context.Context, p provider.Provider) error {
+				return p.CreateNodePool(ctx, clusterID, v1alpha1.NodePool{Name: "c", Size: "s-1vcpu-2gb", Count: 3})
+			},
+			method: http.MethodPost,
+			path:   poolsPath,
+			body:   &godo.KubernetesNodePoolCreateRequest{Name: "c", Size: "s-1vcpu-2gb", Count: 3},
+		},
+		{
+			name: "delete a node pool",
+			call: func(ctx context.Context, p provider.Provider) error {
+				return p.DeleteNodePool(ctx, clusterID, "b")
+			},
+			method: http.MethodDelete,
+			path:   poolsPath + "/00000001-pool-1",
+		},
+		{
+			name: "scale a pool created after a pool was deleted",
+			call: func(ctx context.Context, p provider.Provider) error {
+				if err := p.DeleteNodePool(ctx, clusterID, "a"); err != nil {
+					return err
+				}
+				if err := p.CreateNodePool(ctx, clusterID, v1alpha1.NodePool{Name: "c", Size: "s-1vcpu-2gb", Count: 1}); err != nil {
+					return err
+				}
+				return p.Scale(ctx, clusterID, v1alpha1.NodePool{Name: "c", Size: "s-1vcpu-2gb", Count: 2})
+			},
+			method: http.MethodPut,
+			path:   poolsPath + "/00000001-pool-2",
+			check: func(t *testing.T, s *doserver.Server) {
+				cluster, _ := s.Cluster(clusterID)
+				for _, np := range cluster.NodePools {
+					if np.Name == "b" && np.Count != 1 {
+						t.Errorf("scaling pool c changed pool b to %d nodes", np.Count)
+					}
+				}
+			},
+		},
+		{
+			name: "scale a pool on the second page of node pools",
+			call: func(ctx context.Context, p provider.Provider) error {
+				for i := 0; i < 200; i++ {
+					if err := p.CreateNodePool(ctx, clusterID, v1alpha1.NodePool{Name: fmt.Sprintf("p%d", i), Size: "s-1vcpu-2gb", Count: 1}); err != nil {
+						return err
+					}
+				}
+				return p.Scale(ctx, clusterID, v1alpha1.NodePool{Name: "p199", Size: "s-1vcpu-2gb", Count: 2})
+			},
+			method: http.MethodPut,
+			path:   poolsPath + "/00000001-pool-201",
+			check: func(t *testing.T, s *doserver.Server) {
+				pages := []string{}
+				for _, r := range s.Requests() {
+					if r.Method == http.MethodGet && r.Path == poolsPath {
+						pages = append(pages, r.Query.Get("page"))
+					}
+				}
+				if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+					t.Errorf("listed the node pool pages %v, want 1 and 2", pages)
+				}
+			},
+		},
+		{
+			name: "upgrade",
+			call: func(ctx context.Context, p provider.Provider) error {
+				return p.Upgrade(ctx, clusterID, "1.28.2-do.0")
+			},
+			method: http.MethodPost,
+			path:   "/v2/kubernetes/clusters/" + clusterID + "/upgrade",
+			body:   &godo.KubernetesClusterUpgradeRequest{VersionSlug: "1.28.2-do.0"},
+		},
+		{
+			name: "kubeconfig",
+			call: func(ctx context.Context, p provider.Provider) error {
+				_, err := p.KubeConfig(ctx, clusterID)
+				return err
+			},
+			method: http.MethodGet,
+			path:   "/v2/kubernetes/clusters/" + clusterID + "/kubeconfig",
+			query:  url.Values{"expiry_seconds": {"604800"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := doserver.New()
+			ts := httptest.NewServer(s)
+			defer ts.Close()
+
+			p, err := New(ts.URL).For(provider.Credentials{Token: "token"})
+			if err != nil {
+				t.Fatalf("For: %v", err)
+			}
+			id, err := p.Create(ctx, spec, owner)
+			if err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			if id != clusterID {
+				t.Fatalf("Create returned %s, want %s", id, clusterID)
+			}
+			if tt.call != nil {
+				if err := tt.call(ctx, p); err != nil {
+					t.Fatalf("call: %v", err)
+				}
+			}
+
+			requests := s.Requests()
+			for _, r := range requests {
+				if r.Token != "token" {
+					t.Errorf("%s %s was sent with token %q", r.Method, r.Path, r.Token)
+				}
+			}
+			last := requests[len(requests)-1]
+			if last.Method != tt.method || last.Path != tt.path {
+				t.Fatalf("last request = %s %s, want %s %s", last.Method, last.Path, tt.method, tt.path)
+			}
+			if tt.query != nil && !reflect.DeepEqual(last.Query, tt.query) {
+				t.Errorf("query = %v, want %v", last.Query, tt.query)
+			}
+			if tt.body != nil {
+				got := reflect.New(reflect.TypeOf(tt.body).Elem()).Interface()
+				if err := last.Decode(got); err != nil {
+					t.Fatalf("decoding the body %s: %v", last.Body, err)
+				}
+				if !reflect.DeepEqual(got, tt.body) {
+					t.Errorf("body = %s, want %+v", last.Body, tt.body)
+				}
+			}
+			if tt.check != nil {
+				tt.check(t, s)
+			}
+		})
+	}
+}
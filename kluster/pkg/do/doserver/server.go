@@ -0,0 +1,551 @@
+package doserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/digitalocean/godo"
+)
+
+const clustersPath = "/v2/kubernetes/clusters"
+
+// Page sizes of the list endpoints when per_page is not set and the largest one DO allows
+const (
+	defaultPerPage = 20
+	maxPerPage     = 200
+)
+
+// Request is a call received by the server, recorded so tests can assert the payloads
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+	Token  string /* Bearer token the request was authenticated with */
+}
+
+// Decode the JSON body of the request into v
+func (r Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// Response is a scripted error returned instead of handling matching requests
+type Response struct {
+	Method  string /* HTTP method to match, empty matches all */
+	Path    string /* path.Match pattern, e.g. /v2/kubernetes/clusters/* */
+	Status  int    /* HTTP status code of the error */
+	ID      string /* DO error id, e.g. unprocessable_entity */
+	Message string /* DO error message */
+	Times   int    /* Number of requests to fail, 0 fails all of them */
+}
+
+// Server is an in-memory stand-in for the subset of the DO kubernetes API used by the do provider.
+// Start it with httptest.NewServer and point the do provider at its URL.
+type Server struct {
+	mu       sync.Mutex
+	clusters map[string]*godo.KubernetesCluster
+	pending  map[string]int
+	poolIDs  map[string]int /* Number of node pools ever created in each cluster, IDs of deleted pools are not reused */
+	scripts  []*Response
+	requests []Request
+	nextID   int
+
+	// Versions are the supported version slugs ordered from oldest to newest
+	Versions []string
+	// PendingGets is how many times a cluster is reported provisioning or upgrading before it is running,
+	// and deleted before it is gone
+	PendingGets int
+}
+
+// Create a new server without any clusters
+func New() *Server {
+	return &Server{
+		clusters: map[string]*godo.KubernetesCluster{},
+		pending:  map[string]int{},
+		poolIDs:  map[string]int{},
+		Versions: []string{"1.26.9-do.0", "1.27.6-do.0", "1.28.2-do.0"},
+	}
+}
+
+// Script an error response for the requests matching r
+func (s *Server) Script(r Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts = append(s.scripts, &r)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Cluster returns a copy of the stored cluster
+func (s *Server) Cluster(id string) (*godo.KubernetesCluster, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.clusters[id]
+	if !ok {
+		return nil, false
+	}
+	return copyCluster(c), true
+}
+
+// SetState forces the state of a cluster, e.g. to simulate degraded clusters
+func (s *Server) SetState(id string, state godo.KubernetesClusterStatusState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.clusters[id]; ok {
+		c.Status.State = state
+		delete(s.pending, id)
+	}
+}
+
+// Handle the DO API requests
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Body:   body,
+		Token:  strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "),
+	})
+
+	if resp := s.scripted(r); resp != nil {
+		writeError(w, resp.Status, resp.ID, resp.Message)
+		return
+	}
+
+	if r.URL.Path == "/v2/kubernetes/options" && r.Method == http.MethodGet {
+		s.options(w)
+		return
+	}
+
+	// /v2/kubernetes/clusters[/{id}[/{sub}[/{poolID}]]]
+	if !strings.HasPrefix(r.URL.Path, clustersPath) {
+		writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+		return
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, clustersPath), "/"), "/")
+
+	if parts[0] == "" {
+		switch r.Method {
+		case http.MethodGet:
+			s.listClusters(w, r)
+		case http.MethodPost:
+			s.createCluster(w, body)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+		}
+		return
+	}
+
+	c, ok := s.clusters[parts[0]]
+	if !ok {
+		writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+		return
+	}
+
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		if !s.advance(c) {
+			writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"kubernetes_cluster": c})
+	case len(parts) == 1 && r.Method == http.MethodDelete:
+		// DO reports the cluster as deleted while it is torn down, it is gone after PendingGets polls.
+		// Deleting it again does not extend that.
+		if c.Status.State != godo.KubernetesClusterStatusDeleted {
+			c.Status.State = godo.KubernetesClusterStatusDeleted
+			s.pending[c.ID] = s.PendingGets
+		}
+		w.WriteHeader(http.StatusNoContent)
+	case len(parts) == 2 && parts[1] == "kubeconfig" && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/yaml")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, kubeConfig(c))
+	case len(parts) == 2 && parts[1] == "upgrades" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{"available_upgrade_versions": s.upgrades(c)})
+	case len(parts) == 2 && parts[1] == "upgrade" && r.Method == http.MethodPost:
+		s.upgrade(w, c, body)
+	case len(parts) >= 2 && parts[1] == "node_pools":
+		s.nodePools(w, r, c, parts[2:], body)
+	default:
+		writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+	}
+}
+
+// Return the first scripted response matching the request
+func (s *Server) scripted(r *http.Request) *Response {
+	for i, resp := range s.scripts {
+		if resp.Method != "" && resp.Method != r.Method {
+			continue
+		}
+		if ok, _ := path.Match(resp.Path, r.URL.Path); !ok {
+			continue
+		}
+		if resp.Times > 0 {
+			resp.Times--
+			if resp.Times == 0 {
+				s.scripts = append(s.scripts[:i], s.scripts[i+1:]...)
+			}
+		}
+		return resp
+	}
+	return nil
+}
+
+func (s *Server) options(w http.ResponseWriter) {
+	options := &godo.KubernetesOptions{}
+	for _, v := range s.Versions {
+		options.Versions = append(options.Versions, &godo.KubernetesVersion{
+			Slug:              v,
+			KubernetesVersion: strings.SplitN(v, "-", 2)[0],
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"options": options})
+}
+
+func (s *Server) listClusters(w http.ResponseWriter, r *http.Request) {
+	clusters := []*godo.KubernetesCluster{}
+	for _, c := range s.clusters {
+		if s.advance(c) {
+			clusters = append(clusters, c)
+		}
+	}
+	// Keep the order of the pages stable, the IDs grow with every created cluster
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].ID < clusters[j].ID })
+
+	start, end, links := paginate(r, len(clusters))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"kubernetes_clusters": clusters[start:end],
+		"links":               links,
+		"meta":                &godo.Meta{Total: len(clusters)},
+	})
+}
+
+// Bounds of the items on the page the page and per_page query parameters select out of n items,
+// and the links to the other pages. The next link is only set when there is a next page.
+func paginate(r *http.Request, n int) (int, int, *godo.Links) {
+	query := r.URL.Query()
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := strconv.Atoi(query.Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	start := (page - 1) * perPage
+	if start > n {
+		start = n
+	}
+	end := start + perPage
+	if end > n {
+		end = n
+	}
+	last := (n + perPage - 1) / perPage
+	if last < 1 {
+		last = 1
+	}
+
+	link := func(page int) string {
+		u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path}
+		q := url.Values{}
+		q.Set("page", strconv.Itoa(page))
+		q.Set("per_page", strconv.Itoa(perPage))
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	pages := &godo.Pages{}
+	if page > 1 {
+		pages.First = link(1)
+		pages.Prev = link(page - 1)
+	}
+	if page < last {
+		pages.Next = link(page + 1)
+		pages.Last = link(last)
+	}
+	return start, end, &godo.Links{Pages: pages}
+}
+
+func (s *Server) createCluster(w http.ResponseWriter, body []byte) {
+	req := &godo.KubernetesClusterCreateRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	if req.Name == "" || req.RegionSlug == "" || len(req.NodePools) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "unprocessable_entity", "name, region and node_pools are required")
+		return
+	}
+	version := req.VersionSlug
+	if version == "latest" {
+		version = s.Versions[len(s.Versions)-1]
+	}
+	if !s.supported(version) {
+		writeError(w, http.StatusUnprocessableEntity, "unprocessable_entity", "validation error: invalid version slug")
+		return
+	}
+
+	s.nextID++
+	now := time.Now().UTC()
+	c := &godo.KubernetesCluster{
+		ID:          fmt.Sprintf("%08d-0000-4000-8000-000000000000", s.nextID),
+		Name:        req.Name,
+		RegionSlug:  req.RegionSlug,
+		VersionSlug: version,
+		Tags:        req.Tags,
+		Status:      &godo.KubernetesClusterStatus{State: godo.KubernetesClusterStatusProvisioning},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, np := range req.NodePools {
+		c.NodePools = append(c.NodePools, s.newNodePool(c, np))
+	}
+	s.clusters[c.ID] = c
+	s.pending[c.ID] = s.PendingGets
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{"kubernetes_cluster": c})
+}
+
+func (s *Server) upgrade(w http.ResponseWriter, c *godo.KubernetesCluster, body []byte) {
+	req := &godo.KubernetesClusterUpgradeRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	allowed := false
+	for _, v := range s.upgrades(c) {
+		allowed = allowed || v.Slug == req.VersionSlug
+	}
+	if !allowed {
+		writeError(w, http.StatusUnprocessableEntity, "unprocessable_entity", "cannot upgrade to "+req.VersionSlug)
+		return
+	}
+
+	c.VersionSlug = req.VersionSlug
+	c.Status.State = godo.KubernetesClusterStatusUpgrading
+	s.pending[c.ID] = s.PendingGets
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// Handle /node_pools[/{poolID}] of cluster c
+func (s *Server) nodePools(w http.ResponseWriter, r *http.Request, c *godo.KubernetesCluster, parts []string, body []byte) {
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			start, end, links := paginate(r, len(c.NodePools))
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"node_pools": c.NodePools[start:end],
+				"links":      links,
+				"meta":       &godo.Meta{Total: len(c.NodePools)},
+			})
+		case http.MethodPost:
+			req := &godo.KubernetesNodePoolCreateRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+			for _, np := range c.NodePools {
+				if np.Name == req.Name {
+					writeError(w, http.StatusUnprocessableEntity, "unprocessable_entity", "node pool name already exists")
+					return
+				}
+			}
+			np := s.newNodePool(c, req)
+			c.NodePools = append(c.NodePools, np)
+			writeJSON(w, http.StatusCreated, map[string]interface{}{"node_pool": np})
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+		}
+		return
+	}
+
+	for i, np := range c.NodePools {
+		if np.ID != parts[0] {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(w, http.StatusOK, map[string]interface{}{"node_pool": np})
+		case http.MethodPut:
+			req := &godo.KubernetesNodePoolUpdateRequest{}
+			if err := json.Unmarshal(body, req); err != nil {
+				writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+				return
+			}
+			if req.Name != "" {
+				np.Name = req.Name
+			}
+			if req.Count != nil {
+				np.Count = *req.Count
+				np.Nodes = newNodes(np.ID, np.Count)
+			}
+			if req.AutoScale != nil {
+				np.AutoScale = *req.AutoScale
+			}
+			if req.MinNodes != nil {
+				np.MinNodes = *req.MinNodes
+			}
+			if req.MaxNodes != nil {
+				np.MaxNodes = *req.MaxNodes
+			}
+			writeJSON(w, http.StatusAccepted, map[string]interface{}{"node_pool": np})
+		case http.MethodDelete:
+			c.NodePools = append(c.NodePools[:i], c.NodePools[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method_not_allowed", r.Method)
+		}
+		return
+	}
+	writeError(w, http.StatusNotFound, "not_found", "The resource you were accessing could not be found.")
+}
+
+// Move a provisioning or upgrading cluster towards running and a deleted one towards being gone,
+// it returns false once the cluster is gone
+func (s *Server) advance(c *godo.KubernetesCluster) bool {
+	switch c.Status.State {
+	case godo.KubernetesClusterStatusProvisioning, godo.KubernetesClusterStatusUpgrading, godo.KubernetesClusterStatusDeleted:
+	default:
+		return true
+	}
+	if s.pending[c.ID] > 0 {
+		s.pending[c.ID]--
+		return true
+	}
+	if c.Status.State == godo.KubernetesClusterStatusDeleted {
+		delete(s.clusters, c.ID)
+		delete(s.pending, c.ID)
+		delete(s.poolIDs, c.ID)
+		return false
+	}
+	c.Status.State = godo.KubernetesClusterStatusRunning
+	c.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// Versions newer than the version of the cluster
+func (s *Server) upgrades(c *godo.KubernetesCluster) []*godo.KubernetesVersion {
+	upgrades := []*godo.KubernetesVersion{}
+	newer := false
+	for _, v := range s.Versions {
+		if newer {
+			upgrades = append(upgrades, &godo.KubernetesVersion{
+				Slug:              v,
+				KubernetesVersion: strings.SplitN(v, "-", 2)[0],
+			})
+		}
+		newer = newer || v == c.VersionSlug
+	}
+	return upgrades
+}
+
+func (s *Server) supported(version string) bool {
+	for _, v := range s.Versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) newNodePool(c *godo.KubernetesCluster, req *godo.KubernetesNodePoolCreateRequest) *godo.KubernetesNodePool {
+	id := fmt.Sprintf("%s-pool-%d", c.ID[:8], s.poolIDs[c.ID])
+	s.poolIDs[c.ID]++
+	return &godo.KubernetesNodePool{
+		ID:        id,
+		Name:      req.Name,
+		Size:      req.Size,
+		Count:     req.Count,
+		Tags:      req.Tags,
+		Labels:    req.Labels,
+		AutoScale: req.AutoScale,
+		MinNodes:  req.MinNodes,
+		MaxNodes:  req.MaxNodes,
+		Nodes:     newNodes(id, req.Count),
+	}
+}
+
+func newNodes(poolID string, count int) []*godo.KubernetesNode {
+	nodes := []*godo.KubernetesNode{}
+	for i := 0; i < count; i++ {
+		nodes = append(nodes, &godo.KubernetesNode{
+			ID:     fmt.Sprintf("%s-node-%d", poolID, i),
+			Name:   fmt.Sprintf("%s-%d", poolID, i),
+			Status: &godo.KubernetesNodeStatus{State: "running"},
+		})
+	}
+	return nodes
+}
+
+func kubeConfig(c *godo.KubernetesCluster) string {
+	return fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://%[1]s.k8s.ondigitalocean.invalid
+  name: %[2]s
+contexts:
+- context:
+    cluster: %[2]s
+    user: %[2]s-admin
+  name: %[2]s
+current-context: %[2]s
+users:
+- name: %[2]s-admin
+  user:
+    token: token-%[1]s
+`, c.ID, "do-"+c.RegionSlug+"-"+c.Name)
+}
+
+func copyCluster(c *godo.KubernetesCluster) *godo.KubernetesCluster {
+	out := *c
+	status := *c.Status
+	out.Status = &status
+	out.NodePools = nil
+	for _, np := range c.NodePools {
+		pool := *np
+		out.NodePools = append(out.NodePools, &pool)
+	}
+	return &out
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// Write an error in the DO API error format
+func writeError(w http.ResponseWriter, status int, id, message string) {
+	writeJSON(w, status, map[string]string{
+		"id":         id,
+		"message":    message,
+		"request_id": fmt.Sprintf("doserver-%d", time.Now().UnixNano()),
+	})
+}
@@ -0,0 +1,154 @@
+package doserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/digitalocean/godo"
+)
+
+// godo client of a new server that is closed with the test
+func newClient(t *testing.T) (*Server, *godo.Client) {
+	t.Helper()
+	s := New()
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	client := godo.NewFromToken("token")
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.BaseURL = u
+	return s, client
+}
+
+func createCluster(t *testing.T, client *godo.Client, name string) *godo.KubernetesCluster {
+	t.Helper()
+	cluster, _, err := client.Kubernetes.Create(context.Background(), &godo.KubernetesClusterCreateRequest{
+		Name:        name,
+		RegionSlug:  "nyc1",
+		VersionSlug: "1.28.2-do.0",
+		NodePools:   []*godo.KubernetesNodePoolCreateRequest{{Name: "a", Size: "s-1vcpu-2gb", Count: 1}},
+	})
+	if err != nil {
+		t.Fatalf("creating cluster %s: %v", name, err)
+	}
+	return cluster
+}
+
+func TestDelete(t *testing.T) {
+	ctx := context.Background()
+	s, client := newClient(t)
+	s.PendingGets = 2
+	cluster := createCluster(t, client, "k")
+	s.SetState(cluster.ID, godo.KubernetesClusterStatusRunning)
+
+	if _, err := client.Kubernetes.Delete(ctx, cluster.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	for i := 0; i < s.PendingGets; i++ {
+		got, _, err := client.Kubernetes.Get(ctx, cluster.ID)
+		if err != nil {
+			t.Fatalf("Get %d after the delete: %v", i, err)
+		}
+		if got.Status.State != godo.KubernetesClusterStatusDeleted {
+			t.Fatalf("state of Get %d after the delete = %s, want %s", i, got.Status.State, godo.KubernetesClusterStatusDeleted)
+		}
+		// Deleting the cluster again does not keep it around longer
+		if _, err := client.Kubernetes.Delete(ctx, cluster.ID); err != nil {
+			t.Fatalf("Delete again: %v", err)
+		}
+	}
+	_, resp, err := client.Kubernetes.Get(ctx, cluster.ID)
+	if err == nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Get after the deletion = %v, want 404", err)
+	}
+	if _, ok := s.Cluster(cluster.ID); ok {
+		t.Error("the deleted cluster is still stored")
+	}
+}
+
+func TestListClustersPages(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	for i := 0; i < 5; i++ {
+		createCluster(t, client, fmt.Sprintf("k%d", i))
+	}
+
+	names := []string{}
+	opt := &godo.ListOptions{Page: 1, PerPage: 2}
+	for pages := 1; ; pages++ {
+		clusters, resp, err := client.Kubernetes.List(ctx, opt)
+		if err != nil {
+			t.Fatalf("List page %d: %v", opt.Page, err)
+		}
+		if len(clusters) > opt.PerPage {
+			t.Fatalf("page %d has %d clusters, want at most %d", opt.Page, len(clusters), opt.PerPage)
+		}
+		for _, c := range clusters {
+			names = append(names, c.Name)
+		}
+		if resp.Meta == nil || resp.Meta.Total != 5 {
+			t.Errorf("meta of page %d = %+v, want a total of 5", opt.Page, resp.Meta)
+		}
+		if resp.Links == nil || resp.Links.IsLastPage() {
+			if pages != 3 {
+				t.Errorf("listed %d pages, want 3", pages)
+			}
+			break
+		}
+		opt.Page++
+	}
+
+	want := []string{"k0", "k1", "k2", "k3", "k4"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Errorf("listed clusters %v, want %v", names, want)
+	}
+}
+
+func TestListNodePoolsPages(t *testing.T) {
+	ctx := context.Background()
+	_, client := newClient(t)
+	cluster := createCluster(t, client, "k")
+	for i := 0; i < 2; i++ {
+		_, _, err := client.Kubernetes.CreateNodePool(ctx, cluster.ID, &godo.KubernetesNodePoolCreateRequest{Name: fmt.Sprintf("p%d", i), Size: "s-1vcpu-2gb", Count: 1})
+		if err != nil {
+			t.Fatalf("CreateNodePool: %v", err)
+		}
+	}
+
+	// godo does not return the links of node pool lists, so read the response itself
+	list := func(page int) ([]*godo.KubernetesNodePool, *godo.Links) {
+		t.Helper()
+		resp, err := http.Get(fmt.Sprintf("%s/v2/kubernetes/clusters/%s/node_pools?page=%d&per_page=2", strings.TrimSuffix(client.BaseURL.String(), "/"), cluster.ID, page))
+		if err != nil {
+			t.Fatalf("listing the node pools: %v", err)
+		}
+		defer resp.Body.Close()
+		root := struct {
+			NodePools []*godo.KubernetesNodePool `json:"node_pools"`
+			Links     *godo.Links                `json:"links"`
+		}{}
+		if err := json.NewDecoder(resp.Body).Decode(&root); err != nil {
+			t.Fatalf("decoding the node pools: %v", err)
+		}
+		return root.NodePools, root.Links
+	}
+
+	pools, links := list(2)
+	if len(pools) != 1 || pools[0].Name != "p1" {
+		t.Errorf("second page has the pools %+v, want only p1", pools)
+	}
+	if links == nil || !links.IsLastPage() {
+		t.Errorf("links of the last page = %+v, want no next page", links)
+	}
+	if _, links := list(1); links == nil || links.IsLastPage() {
+		t.Error("the first of two pages has no next page")
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Paths of the probes of the kubelet
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Check is a named check of a probe, it returns an error when it fails
+type Check struct {
+	Name  string
+	Check func() error
+}
+
+// Handler runs the checks on every request and answers 200 when all of them pass and 500 otherwise.
+// The result of each check is listed when one fails or the verbose query parameter is set.
+func Handler(checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := strings.Builder{}
+		failed := false
+		for _, c := range checks {
+			if err := c.Check(); err != nil {
+				failed = true
+				fmt.Fprintf(&out, "[-]%s failed: %s\n", c.Name, err.Error())
+				continue
+			}
+			fmt.Fprintf(&out, "[+]%s ok\n", c.Name)
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(w, "%s%s check failed\n", out.String(), strings.TrimPrefix(r.URL.Path, "/"))
+			return
+		}
+		if _, verbose := r.URL.Query()["verbose"]; verbose {
+			fmt.Fprint(w, out.String())
+		}
+		fmt.Fprint(w, "ok\n")
+	})
+}
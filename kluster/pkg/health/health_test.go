@@ -0,0 +1,81 @@
+package health
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	pass := Check{Name: "informers", Check: func() error { return nil }}
+	fail := Check{Name: "leader-election", Check: func() error { return errors.New("lease expired") }}
+
+	tests := []struct {
+		name     string
+		path     string
+		checks   []Check
+		wantCode int
+		wantBody []string /* Parts the body has to contain */
+		noBody   []string /* Parts the body must not contain */
+	}{
+		{
+			name:     "ready",
+			path:     ReadinessPath,
+			checks:   []Check{pass},
+			wantCode: http.StatusOK,
+			wantBody: []string{"ok\n"},
+			noBody:   []string{"[+]informers ok"},
+		},
+		{
+			name:     "ready without checks",
+			path:     ReadinessPath,
+			wantCode: http.StatusOK,
+			wantBody: []string{"ok\n"},
+		},
+		{
+			name:     "verbose lists the checks",
+			path:     ReadinessPath + "?verbose",
+			checks:   []Check{pass},
+			wantCode: http.StatusOK,
+			wantBody: []string{"[+]informers ok\n", "ok\n"},
+		},
+		{
+			name:     "not ready",
+			path:     ReadinessPath,
+			checks:   []Check{pass, fail},
+			wantCode: http.StatusInternalServerError,
+			wantBody: []string{"[+]informers ok\n", "[-]leader-election failed: lease expired\n", "readyz check failed\n"},
+		},
+		{
+			name:     "not alive",
+			path:     LivenessPath,
+			checks:   []Check{fail},
+			wantCode: http.StatusInternalServerError,
+			wantBody: []string{"[-]leader-election failed: lease expired\n", "healthz check failed\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler(tt.checks...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			body := rec.Body.String()
+			for _, want := range tt.wantBody {
+				if !strings.Contains(body, want) {
+					t.Errorf("body %q does not contain %q", body, want)
+				}
+			}
+			for _, unwanted := range tt.noBody {
+				if strings.Contains(body, unwanted) {
+					t.Errorf("body %q contains %q", body, unwanted)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package metrics
+
+import (
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	klister "kluster/pkg/client/listers/siqi.dev/v1alpha1"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+var (
+	klustersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "klusters"),
+		"Number of klusters by phase, the phase is the progress in their status.",
+		[]string{"phase"}, nil,
+	)
+	provisioningDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "provisioning_since_timestamp_seconds"),
+		"Unix time since when a kluster is provisioning, only set while its Provisioning condition is true.",
+		[]string{"namespace", "name"}, nil,
+	)
+)
+
+// Phase of klusters the controller did not look at yet
+const phasePending = "pending"
+
+// Reports the klusters per phase from the informer cache every time the metrics are scraped
+type klusterCollector struct {
+	lister klister.KlusterLister
+}
+
+// RegisterKlusters adds the gauges of the klusters in the lister to Registry
+func RegisterKlusters(lister klister.KlusterLister) {
+	Registry.MustRegister(&klusterCollector{lister: lister})
+}
+
+func (c *klusterCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- klustersDesc
+	ch <- provisioningDesc
+}
+
+func (c *klusterCollector) Collect(ch chan<- prometheus.Metric) {
+	klusters, err := c.lister.List(labels.Everything())
+	if err != nil {
+		klog.Errorf("error %s, listing klusters for the metrics\n", err.Error())
+		return
+	}
+
+	phases := map[string]int{}
+	for _, kluster := range klusters {
+		phases[phase(kluster)]++
+
+		provisioning := meta.FindStatusCondition(kluster.Status.Conditions, v1alpha1.ConditionProvisioning)
+		if provisioning != nil && provisioning.Status == metav1.ConditionTrue {
+			ch <- prometheus.MustNewConstMetric(provisioningDesc, prometheus.GaugeValue,
+				float64(provisioning.LastTransitionTime.Unix()), kluster.Namespace, kluster.Name)
+		}
+	}
+	for p, n := range phases {
+		ch <- prometheus.MustNewConstMetric(klustersDesc, prometheus.GaugeValue, float64(n), p)
+	}
+}
+
+func phase(kluster *v1alpha1.Kluster) string {
+	if kluster.Status.Progress == "" {
+		return phasePending
+	}
+	return kluster.Status.Progress
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace of all the metrics of the controller
+const namespace = "kluster"
+
+// Results of a reconcile
+const (
+	ResultSuccess = "success" /* The cloud cluster matches the spec */
+	ResultRequeue = "requeue" /* The cloud is still working on the cluster */
+	ResultError   = "error"   /* The reconcile failed and is retried */
+)
+
+// Registry holds the metrics of the controller, it is served by Handler
+var Registry = prometheus.NewRegistry()
+
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Number of reconciles of a kluster by result.",
+	}, []string{"namespace", "name", "result"})
+
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of failed reconciles of a kluster.",
+	}, []string{"namespace", "name"})
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Time a reconcile of a kluster took.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"namespace", "name"})
+
+	providerRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_requests_total",
+		Help:      "Number of calls to the cloud provider by operation and result code.",
+	}, []string{"provider", "operation", "code"})
+
+	providerDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Time a call to the cloud provider took by operation.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		reconcileTotal,
+		reconcileErrors,
+		reconcileDuration,
+		providerRequests,
+		providerDuration,
+	)
+}
+
+// Handler serves the metrics of Registry in the prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveReconcile records a reconcile of the kluster namespace/name that started at start
+func ObserveReconcile(ns, name, result string, start time.Time) {
+	reconcileTotal.WithLabelValues(ns, name, result).Inc()
+	reconcileDuration.WithLabelValues(ns, name).Observe(time.Since(start).Seconds())
+	if result == ResultError {
+		reconcileErrors.WithLabelValues(ns, name).Inc()
+	}
+}
+
+// ForgetKluster drops the reconcile metrics of a deleted kluster, so they do not pile up
+func ForgetKluster(ns, name string) {
+	labels := prometheus.Labels{"namespace": ns, "name": name}
+	reconcileTotal.DeletePartialMatch(labels)
+	reconcileErrors.DeletePartialMatch(labels)
+	reconcileDuration.DeletePartialMatch(labels)
+}
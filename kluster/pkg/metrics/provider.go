@@ -0,0 +1,136 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+)
+
+// Instrument wraps every provider of the registry to record the latency and result of its calls
+func Instrument(r provider.Registry) provider.Registry {
+	instrumented := provider.Registry{}
+	for name, f := range r {
+		instrumented[name] = &instrumentedFactory{name: name, factory: f}
+	}
+	return instrumented
+}
+
+// Wraps the providers built by a factory
+type instrumentedFactory struct {
+	name    string           /* Name of the provider in the registry */
+	factory provider.Factory /* Factory that builds the providers */
+}
+
+func (f *instrumentedFactory) For(creds provider.Credentials) (provider.Provider, error) {
+	p, err := f.factory.For(creds)
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedProvider{name: f.name, provider: p}, nil
+}
+
+// Records the calls to a provider under its registry name
+type instrumentedProvider struct {
+	name     string            /* Name of the provider in the registry */
+	provider provider.Provider /* Provider that makes the calls */
+}
+
+var _ provider.Provider = &instrumentedProvider{}
+
+// Record a call to operation that started at start and returned err
+func (p *instrumentedProvider) observe(operation string, start time.Time, err error) {
+	providerDuration.WithLabelValues(p.name, operation).Observe(time.Since(start).Seconds())
+	providerRequests.WithLabelValues(p.name, operation, errorCode(err)).Inc()
+}
+
+// The HTTP status code of API errors, or a short name of the error for the rest
+func errorCode(err error) string {
+	var apiErr *provider.APIError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, provider.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, provider.ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return "timeout"
+	}
+	return "error"
+}
+
+func (p *instrumentedProvider) Create(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (string, error) {
+	start := time.Now()
+	id, err := p.provider.Create(ctx, spec, owner)
+	p.observe("create", start, err)
+	return id, err
+}
+
+func (p *instrumentedProvider) Get(ctx context.Context, id string) (*provider.Cluster, error) {
+	start := time.Now()
+	cluster, err := p.provider.Get(ctx, id)
+	p.observe("get", start, err)
+	return cluster, err
+}
+
+func (p *instrumentedProvider) Find(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (*provider.Cluster, error) {
+	start := time.Now()
+	cluster, err := p.provider.Find(ctx, spec, owner)
+	p.observe("find", start, err)
+	return cluster, err
+}
+
+func (p *instrumentedProvider) Delete(ctx context.Context, id string) error {
+	start := time.Now()
+	err := p.provider.Delete(ctx, id)
+	p.observe("delete", start, err)
+	return err
+}
+
+func (p *instrumentedProvider) Scale(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	start := time.Now()
+	err := p.provider.Scale(ctx, id, pool)
+	p.observe("scale", start, err)
+	return err
+}
+
+func (p *instrumentedProvider) CreateNodePool(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	start := time.Now()
+	err := p.provider.CreateNodePool(ctx, id, pool)
+	p.observe("create_node_pool", start, err)
+	return err
+}
+
+func (p *instrumentedProvider) DeleteNodePool(ctx context.Context, id, name string) error {
+	start := time.Now()
+	err := p.provider.DeleteNodePool(ctx, id, name)
+	p.observe("delete_node_pool", start, err)
+	return err
+}
+
+func (p *instrumentedProvider) Upgrades(ctx context.Context, id string) ([]string, error) {
+	start := time.Now()
+	versions, err := p.provider.Upgrades(ctx, id)
+	p.observe("upgrades", start, err)
+	return versions, err
+}
+
+func (p *instrumentedProvider) Upgrade(ctx context.Context, id, version string) error {
+	start := time.Now()
+	err := p.provider.Upgrade(ctx, id, version)
+	p.observe("upgrade", start, err)
+	return err
+}
+
+func (p *instrumentedProvider) KubeConfig(ctx context.Context, id string) (*provider.KubeConfig, error) {
+	start := time.Now()
+	config, err := p.provider.KubeConfig(ctx, id)
+	p.observe("kubeconfig", start, err)
+	return config, err
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"kluster/pkg/provider"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "success", want: "ok"},
+		{name: "api error", err: &provider.APIError{StatusCode: http.StatusTooManyRequests, Err: errors.New("rate limited")}, want: "429"},
+		{name: "wrapped api error", err: fmt.Errorf("getting cluster: %w", &provider.APIError{StatusCode: http.StatusInternalServerError, Err: errors.New("failed")}), want: "500"},
+		{name: "api error is preferred over what it wraps", err: &provider.APIError{StatusCode: http.StatusNotFound, Err: provider.ErrNotFound}, want: "404"},
+		{name: "not found", err: fmt.Errorf("getting cluster: %w", provider.ErrNotFound), want: "not_found"},
+		{name: "invalid credentials", err: provider.ErrInvalidCredentials, want: "invalid_credentials"},
+		{name: "deadline", err: fmt.Errorf("getting cluster: %w", context.DeadlineExceeded), want: "timeout"},
+		{name: "cancelled", err: context.Canceled, want: "timeout"},
+		{name: "other error", err: errors.New("failed"), want: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
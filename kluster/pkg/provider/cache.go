@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Cache keeps the provider built for each kluster, so that klusters do not set up a new client
+// on every reconcile. A provider is built again when the credential source of the kluster changed,
+// and dropped by Registry.Forget once the kluster is deleted.
+func Cache(r Registry) Registry {
+	cached := Registry{}
+	for name, f := range r {
+		cached[name] = &cachedFactory{factory: f, providers: map[types.UID]cachedProvider{}}
+	}
+	return cached
+}
+
+type cachedFactory struct {
+	factory Factory
+
+	mu        sync.Mutex                   /* Guards providers */
+	providers map[types.UID]cachedProvider /* Providers by the UID of their kluster */
+}
+
+type cachedProvider struct {
+	source          types.UID
+	resourceVersion string
+	provider        Provider
+}
+
+func (f *cachedFactory) For(creds Credentials) (Provider, error) {
+	// Credentials that do not come from a source can not be told apart
+	if creds.SecretUID == "" || creds.Kluster == "" {
+		return f.factory.For(creds)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cached, ok := f.providers[creds.Kluster]; ok && cached.source == creds.SecretUID && cached.resourceVersion == creds.ResourceVersion {
+		return cached.provider, nil
+	}
+	p, err := f.factory.For(creds)
+	if err != nil {
+		return nil, err
+	}
+	// Replaces the provider of another source, or of an older version of the source
+	f.providers[creds.Kluster] = cachedProvider{source: creds.SecretUID, resourceVersion: creds.ResourceVersion, provider: p}
+	return p, nil
+}
+
+// Forget drops the provider of the kluster
+func (f *cachedFactory) Forget(kluster types.UID) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.providers, kluster)
+}
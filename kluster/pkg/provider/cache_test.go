@@ -0,0 +1,112 @@
+package provider
+
+import "testing"
+
+// Provider told apart by the call of For that built it
+type builtProvider struct {
+	Provider
+	n int
+}
+
+// Factory that counts how often it was asked for a provider
+type countingFactory struct {
+	built int
+}
+
+func (f *countingFactory) For(creds Credentials) (Provider, error) {
+	f.built++
+	return &builtProvider{n: f.built}, nil
+}
+
+func TestCache(t *testing.T) {
+	creds := Credentials{Token: "token", SecretUID: "secret-1", ResourceVersion: "1", Kluster: "kluster-1"}
+	with := func(change func(c *Credentials)) Credentials {
+		c := creds
+		change(&c)
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		first  Credentials
+		forget bool /* Forget the kluster between the calls */
+		second Credentials
+		reused bool
+	}{
+		{
+			name:   "same kluster and source",
+			first:  creds,
+			second: creds,
+			reused: true,
+		},
+		{
+			name:   "changed resourceVersion",
+			first:  creds,
+			second: with(func(c *Credentials) { c.ResourceVersion = "2" }),
+		},
+		{
+			name:   "other source",
+			first:  creds,
+			second: with(func(c *Credentials) { c.SecretUID = "secret-2" }),
+		},
+		{
+			name:   "other kluster",
+			first:  creds,
+			second: with(func(c *Credentials) { c.Kluster = "kluster-2" }),
+		},
+		{
+			name:   "forgotten kluster",
+			first:  creds,
+			forget: true,
+			second: creds,
+		},
+		{
+			name:   "credentials without a source",
+			first:  with(func(c *Credentials) { c.SecretUID = "" }),
+			second: with(func(c *Credentials) { c.SecretUID = "" }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &countingFactory{}
+			r := Cache(Registry{"test": f})
+
+			first, err := r["test"].For(tt.first)
+			if err != nil {
+				t.Fatalf("For: %v", err)
+			}
+			if tt.forget {
+				r.Forget(tt.first.Kluster)
+			}
+			second, err := r["test"].For(tt.second)
+			if err != nil {
+				t.Fatalf("For: %v", err)
+			}
+
+			if reused := first == second; reused != tt.reused {
+				t.Errorf("reused = %v, want %v", reused, tt.reused)
+			}
+			if want := map[bool]int{true: 1, false: 2}[tt.reused]; f.built != want {
+				t.Errorf("the factory built %d providers, want %d", f.built, want)
+			}
+		})
+	}
+}
+
+func TestCacheKeepsTheLatestProvider(t *testing.T) {
+	f := &countingFactory{}
+	r := Cache(Registry{"test": f})
+	creds := Credentials{Token: "token", SecretUID: "secret-1", ResourceVersion: "1", Kluster: "kluster-1"}
+
+	r["test"].For(creds)
+	creds.ResourceVersion = "2"
+	replaced, _ := r["test"].For(creds)
+	again, _ := r["test"].For(creds)
+	if again != replaced {
+		t.Error("the provider built for the new resourceVersion was not reused")
+	}
+	if f.built != 2 {
+		t.Errorf("the factory built %d providers, want 2", f.built)
+	}
+}
@@ -0,0 +1,415 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+)
+
+// Name of the fake provider in spec.provider
+const Name = "fake"
+
+// Operation of the provider that a failure can be injected into
+type Operation string
+
+const (
+	OpCreate     Operation = "create"
+	OpGet        Operation = "get"
+	OpFind       Operation = "find"
+	OpDelete     Operation = "delete"
+	OpScale      Operation = "scale"
+	OpAddPool    Operation = "createnodepool"
+	OpDeletePool Operation = "deletenodepool"
+	OpUpgrade    Operation = "upgrade"
+	OpUpgrades   Operation = "upgrades"
+	OpKubeConfig Operation = "kubeconfig"
+)
+
+// ErrInjected is returned by calls that fail because of the failure rate
+var ErrInjected = errors.New("injected failure")
+
+// Options control how the fake clusters move through their lifecycle
+type Options struct {
+	ProvisioningDelay time.Duration /* Time a new cluster stays in provisioning before running */
+	DeletionDelay     time.Duration /* Time a deleted cluster stays in deleting before it is gone */
+	UpgradeDelay      time.Duration /* Time a cluster stays in upgrading */
+	FailureRate       float64       /* Probability in [0, 1] that any call fails with ErrInjected */
+	Versions          []string      /* Supported version slugs ordered from oldest to newest, DefaultVersions if empty */
+}
+
+// DefaultVersions are the version slugs supported when Options.Versions is empty
+var DefaultVersions = []string{"1.26.9-do.0", "1.27.6-do.0", "1.28.2-do.0"}
+
+// Provider keeps simulated clusters in memory, it is safe for concurrent use
+type Provider struct {
+	mu       sync.Mutex
+	opts     Options
+	clusters map[string]*cluster
+	failures map[Operation][]error
+	nextID   int
+
+	// Now returns the current time, it can be replaced to control the clock
+	Now func() time.Time
+}
+
+// Simulated cluster with the times of its lifecycle transitions
+type cluster struct {
+	provider.Cluster
+	createdAt     time.Time
+	deletedAt     time.Time
+	upgradedAt    time.Time
+	targetVersion string
+	nextPool      int
+}
+
+var _ provider.Provider = &Provider{}
+var _ provider.Factory = &Provider{}
+
+// Create a new fake provider without any clusters
+func New(opts Options) *Provider {
+	if len(opts.Versions) == 0 {
+		opts.Versions = DefaultVersions
+	}
+	return &Provider{
+		opts:     opts,
+		clusters: map[string]*cluster{},
+		failures: map[Operation][]error{},
+		Now:      time.Now,
+	}
+}
+
+// For returns the fake itself, all credentials share the same simulated cloud
+func (p *Provider) For(creds provider.Credentials) (provider.Provider, error) {
+	return p, nil
+}
+
+// InjectFailure makes the next call of op return err, multiple injected errors are returned in order
+func (p *Provider) InjectFailure(op Operation, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures[op] = append(p.failures[op], err)
+}
+
+// Create a simulated cluster that starts in provisioning
+func (p *Provider) Create(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpCreate); err != nil {
+		return "", err
+	}
+
+	if len(spec.NodePools) == 0 {
+		return "", fmt.Errorf("kluster %s has no node pools", spec.Name)
+	}
+
+	version := spec.Version
+	if version == "" || version == "latest" {
+		version = p.opts.Versions[len(p.opts.Versions)-1]
+	}
+
+	p.nextID++
+	id := fmt.Sprintf("fake-%d", p.nextID)
+	c := &cluster{
+		Cluster: provider.Cluster{
+			ID:      id,
+			Name:    spec.Name,
+			Region:  spec.Region,
+			Version: version,
+			Tags:    []string{owner},
+		},
+		createdAt: p.Now(),
+	}
+	for _, np := range spec.NodePools {
+		c.addNodePool(np)
+	}
+	p.clusters[id] = c
+
+	return id, nil
+}
+
+// Get the simulated cluster in the state it has reached by now
+func (p *Provider) Get(ctx context.Context, id string) (*provider.Cluster, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpGet); err != nil {
+		return nil, err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.view(), nil
+}
+
+// Find the simulated cluster with the name and the owner tag
+func (p *Provider) Find(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (*provider.Cluster, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpFind); err != nil {
+		return nil, err
+	}
+
+	for id, c := range p.clusters {
+		if c.Name != spec.Name || !c.HasTag(owner) {
+			continue
+		}
+		// Move the cluster forward, it may be gone by now
+		if c, err := p.get(id); err == nil {
+			return c.view(), nil
+		}
+	}
+	return nil, provider.ErrNotFound
+}
+
+// Copy of the simulated cluster as the provider sees it
+func (c *cluster) view() *provider.Cluster {
+	out := c.Cluster
+	out.NodePools = append([]provider.NodePool(nil), c.NodePools...)
+	for i := range out.NodePools {
+		// Nodes only run once the cluster has been provisioned
+		if c.State != provider.StateProvisioning {
+			out.NodePools[i].Ready = out.NodePools[i].Count
+		}
+	}
+	out.Tags = append([]string(nil), c.Tags...)
+	return &out
+}
+
+// Delete moves the simulated cluster to deleting, it is gone after the deletion delay
+func (p *Provider) Delete(ctx context.Context, id string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpDelete); err != nil {
+		return err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return err
+	}
+	if c.deletedAt.IsZero() {
+		c.deletedAt = p.Now()
+		c.State = provider.StateDeleting
+	}
+	return nil
+}
+
+// Scale the simulated node pool with the same name
+func (p *Provider) Scale(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpScale); err != nil {
+		return err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return err
+	}
+	for i := range c.NodePools {
+		if c.NodePools[i].Name == pool.Name {
+			if !pool.AutoScale {
+				c.NodePools[i].Count = pool.Count
+			}
+			c.NodePools[i].AutoScale = pool.AutoScale
+			c.NodePools[i].MinNodes = pool.MinNodes
+			c.NodePools[i].MaxNodes = pool.MaxNodes
+			return nil
+		}
+	}
+	return fmt.Errorf("node pool %s not found in cluster %s", pool.Name, id)
+}
+
+// Add a node pool to the simulated cluster
+func (p *Provider) CreateNodePool(ctx context.Context, id string, pool v1alpha1.NodePool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpAddPool); err != nil {
+		return err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return err
+	}
+	for _, np := range c.NodePools {
+		if np.Name == pool.Name {
+			return fmt.Errorf("node pool %s already exists in cluster %s", pool.Name, id)
+		}
+	}
+	c.addNodePool(pool)
+	return nil
+}
+
+// Delete the node pool with the name from the simulated cluster
+func (p *Provider) DeleteNodePool(ctx context.Context, id, name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpDeletePool); err != nil {
+		return err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return err
+	}
+	for i, np := range c.NodePools {
+		if np.Name == name {
+			c.NodePools = append(c.NodePools[:i], c.NodePools[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("node pool %s not found in cluster %s", name, id)
+}
+
+// Upgrades lists the supported versions that are newer than the version of the simulated cluster
+func (p *Provider) Upgrades(ctx context.Context, id string) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpUpgrades); err != nil {
+		return nil, err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return nil, err
+	}
+	return p.upgrades(c), nil
+}
+
+// Versions newer than the version of the cluster, must be called with the lock held
+func (p *Provider) upgrades(c *cluster) []string {
+	versions := []string{}
+	for _, v := range p.opts.Versions {
+		if cmp, err := provider.CompareVersions(v, c.Version); err == nil && cmp > 0 {
+			versions = append(versions, v)
+		}
+	}
+	return versions
+}
+
+// Upgrade moves the simulated cluster to upgrading, the version changes after the upgrade delay
+func (p *Provider) Upgrade(ctx context.Context, id, version string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpUpgrade); err != nil {
+		return err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return err
+	}
+	if c.State != provider.StateRunning {
+		return fmt.Errorf("cluster %s is %s, only running clusters can be upgraded", id, c.State)
+	}
+	available := false
+	for _, v := range p.upgrades(c) {
+		available = available || v == version
+	}
+	if !available {
+		return fmt.Errorf("cluster %s can not be upgraded from %s to %s", id, c.Version, version)
+	}
+	c.upgradedAt = p.Now()
+	c.targetVersion = version
+	c.State = provider.StateUpgrading
+	return nil
+}
+
+// KubeConfig returns a kubeconfig pointing at a made up endpoint of the simulated cluster
+func (p *Provider) KubeConfig(ctx context.Context, id string) (*provider.KubeConfig, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.fail(OpKubeConfig); err != nil {
+		return nil, err
+	}
+
+	c, err := p.get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://%[1]s.fake.invalid
+  name: %[2]s
+contexts:
+- context:
+    cluster: %[2]s
+    user: %[2]s-admin
+  name: %[2]s
+current-context: %[2]s
+users:
+- name: %[2]s-admin
+  user:
+    token: fake-token-%[3]d
+`, c.ID, c.Name, p.Now().Unix())
+
+	return &provider.KubeConfig{
+		Data:      []byte(data),
+		ExpiresAt: p.Now().Add(7 * 24 * time.Hour),
+	}, nil
+}
+
+func (c *cluster) addNodePool(np v1alpha1.NodePool) {
+	c.nextPool++
+	c.NodePools = append(c.NodePools, provider.NodePool{
+		ID:        fmt.Sprintf("%s-pool-%d", c.ID, c.nextPool),
+		Name:      np.Name,
+		Size:      np.Size,
+		Count:     np.Count,
+		AutoScale: np.AutoScale,
+		MinNodes:  np.MinNodes,
+		MaxNodes:  np.MaxNodes,
+	})
+}
+
+// Get the cluster and move it forward in its lifecycle, must be called with the lock held
+func (p *Provider) get(id string) (*cluster, error) {
+	c, ok := p.clusters[id]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+
+	now := p.Now()
+	switch {
+	case !c.deletedAt.IsZero():
+		if !now.Before(c.deletedAt.Add(p.opts.DeletionDelay)) {
+			delete(p.clusters, id)
+			return nil, provider.ErrNotFound
+		}
+		c.State = provider.StateDeleting
+	case !c.upgradedAt.IsZero():
+		if !now.Before(c.upgradedAt.Add(p.opts.UpgradeDelay)) {
+			c.Version = c.targetVersion
+			c.upgradedAt = time.Time{}
+			c.State = provider.StateRunning
+		}
+	case now.Before(c.createdAt.Add(p.opts.ProvisioningDelay)):
+		c.State = provider.StateProvisioning
+	default:
+		c.State = provider.StateRunning
+	}
+	return c, nil
+}
+
+// Return the injected failure of op if there is one, must be called with the lock held
+func (p *Provider) fail(op Operation) error {
+	if errs := p.failures[op]; len(errs) > 0 {
+		p.failures[op] = errs[1:]
+		return errs[0]
+	}
+	if p.opts.FailureRate > 0 && rand.Float64() < p.opts.FailureRate {
+		return fmt.Errorf("%s: %w", op, ErrInjected)
+	}
+	return nil
+}
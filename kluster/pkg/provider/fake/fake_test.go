@@ -0,0 +1,124 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/provider"
+)
+
+var spec = v1alpha1.KlusterSpec{
+	Name:      "k",
+	Region:    "nyc1",
+	Version:   "1.27.6-do.0",
+	NodePools: []v1alpha1.NodePool{{Name: "a", Size: "s-1vcpu-2gb", Count: 2}},
+}
+
+// Fake provider whose clock only moves with the returned function
+func newClocked(opts Options) (*Provider, func(d time.Duration)) {
+	p := New(opts)
+	now := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	p.Now = func() time.Time { return now }
+	return p, func(d time.Duration) { now = now.Add(d) }
+}
+
+// State of the cluster or "gone" once the provider does not know it anymore
+func state(t *testing.T, p *Provider, id string) string {
+	t.Helper()
+	cluster, err := p.Get(context.Background(), id)
+	if errors.Is(err, provider.ErrNotFound) {
+		return "gone"
+	}
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	return cluster.State
+}
+
+func TestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	p, advance := newClocked(Options{ProvisioningDelay: time.Minute, UpgradeDelay: time.Minute, DeletionDelay: time.Minute})
+
+	id, err := p.Create(ctx, spec, "owner")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if got := state(t, p, id); got != provider.StateProvisioning {
+		t.Fatalf("state after create = %s, want %s", got, provider.StateProvisioning)
+	}
+	advance(time.Minute)
+	if got := state(t, p, id); got != provider.StateRunning {
+		t.Fatalf("state after the provisioning delay = %s, want %s", got, provider.StateRunning)
+	}
+
+	if err := p.Upgrade(ctx, id, "1.28.2-do.0"); err != nil {
+		t.Fatalf("Upgrade: %v", err)
+	}
+	cluster, _ := p.Get(ctx, id)
+	if cluster.State != provider.StateUpgrading || cluster.Version != "1.27.6-do.0" {
+		t.Fatalf("cluster after upgrade = %s %s, want upgrading 1.27.6-do.0", cluster.State, cluster.Version)
+	}
+	advance(time.Minute)
+	cluster, _ = p.Get(ctx, id)
+	if cluster.State != provider.StateRunning || cluster.Version != "1.28.2-do.0" {
+		t.Fatalf("cluster after the upgrade delay = %s %s, want running 1.28.2-do.0", cluster.State, cluster.Version)
+	}
+
+	if err := p.Delete(ctx, id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := state(t, p, id); got != provider.StateDeleting {
+		t.Fatalf("state after delete = %s, want %s", got, provider.StateDeleting)
+	}
+	advance(time.Minute)
+	if got := state(t, p, id); got != "gone" {
+		t.Fatalf("state after the deletion delay = %s, want the cluster gone", got)
+	}
+}
+
+func TestInjectFailure(t *testing.T) {
+	ctx := context.Background()
+	p := New(Options{})
+	first, second := errors.New("first"), errors.New("second")
+	p.InjectFailure(OpCreate, first)
+	p.InjectFailure(OpCreate, second)
+
+	// Other operations are not affected
+	if _, err := p.Find(ctx, spec, "owner"); !errors.Is(err, provider.ErrNotFound) {
+		t.Fatalf("Find error = %v, want ErrNotFound", err)
+	}
+	for _, want := range []error{first, second} {
+		if _, err := p.Create(ctx, spec, "owner"); err != want {
+			t.Fatalf("Create error = %v, want %v", err, want)
+		}
+	}
+	id, err := p.Create(ctx, spec, "owner")
+	if err != nil {
+		t.Fatalf("Create after the injected failures: %v", err)
+	}
+	if id != "fake-1" {
+		t.Errorf("Create = %s, want fake-1 as the failed calls created nothing", id)
+	}
+}
+
+func TestFailureRate(t *testing.T) {
+	ctx := context.Background()
+
+	p := New(Options{FailureRate: 1})
+	if _, err := p.Create(ctx, spec, "owner"); !errors.Is(err, ErrInjected) {
+		t.Errorf("Create error with failure rate 1 = %v, want ErrInjected", err)
+	}
+	if _, err := p.Get(ctx, "fake-1"); !errors.Is(err, ErrInjected) {
+		t.Errorf("Get error with failure rate 1 = %v, want ErrInjected", err)
+	}
+
+	p = New(Options{FailureRate: 0})
+	for i := 0; i < 100; i++ {
+		if _, err := p.Create(ctx, spec, "owner"); err != nil {
+			t.Fatalf("Create error with failure rate 0 = %v", err)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// Default is the provider used when a kluster does not set spec.provider
+const Default = "digitalocean"
+
+// Cluster states reported by providers, named after the DO cluster states
+const (
+	StateProvisioning = "provisioning"
+	StateRunning      = "running"
+	StateDegraded     = "degraded"
+	StateUpgrading    = "upgrading"
+	StateError        = "error"
+	StateDeleting     = "deleting"
+	StateDeleted      = "deleted"
+)
+
+// ErrNotFound is returned when the cluster does not exist in the cloud
+var ErrNotFound = errors.New("cluster not found")
+
+// ErrInvalidCredentials is returned when the credentials are missing or rejected by the cloud
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrUnknownProvider is returned for a spec.provider that is not in the registry
+var ErrUnknownProvider = errors.New("unknown provider")
+
+// APIError is an error response of the cloud API, it keeps the HTTP status code of the response
+type APIError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Cluster is the provider independent view of a cloud cluster
+type Cluster struct {
+	ID        string
+	Name      string
+	Region    string
+	Version   string
+	State     string
+	Tags      []string
+	NodePools []NodePool
+}
+
+// HasTag reports whether the cluster is tagged with tag
+func (c *Cluster) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// OwnerTag is put on cloud clusters to record the uid of the kluster that owns them
+func OwnerTag(uid types.UID) string {
+	return "kluster-owner:" + string(uid)
+}
+
+// NodePool is the provider independent view of a node pool of a cloud cluster
+type NodePool struct {
+	ID    string
+	Name  string
+	Size  string
+	Count int
+	Ready int /* Number of nodes that are running */
+
+	AutoScale bool
+	MinNodes  int
+	MaxNodes  int
+}
+
+// CompareVersions compares two version slugs like 1.28.2-do.0 by their kubernetes version,
+// it returns -1, 0 or 1 when a is older than, the same as or newer than b
+func CompareVersions(a, b string) (int, error) {
+	va, err := version.ParseGeneric(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := version.ParseGeneric(b)
+	if err != nil {
+		return 0, err
+	}
+	return va.Compare(vb.String())
+}
+
+// KubeConfig is the admin kubeconfig of a cloud cluster
+type KubeConfig struct {
+	Data      []byte
+	ExpiresAt time.Time
+}
+
+// Provider manages the lifecycle of clusters in one cloud
+type Provider interface {
+	// Create the cluster described by spec tagged with the owner tag and return its ID
+	Create(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (string, error)
+	// Get the current state of the cluster
+	Get(ctx context.Context, id string) (*Cluster, error)
+	// Find the cluster with the name in spec that is tagged with the owner tag, ErrNotFound if there is none
+	Find(ctx context.Context, spec v1alpha1.KlusterSpec, owner string) (*Cluster, error)
+	// Delete the cluster
+	Delete(ctx context.Context, id string) error
+	// Scale resizes the node pool with the same name as pool
+	Scale(ctx context.Context, id string, pool v1alpha1.NodePool) error
+	// CreateNodePool adds the node pool to the cluster
+	CreateNodePool(ctx context.Context, id string, pool v1alpha1.NodePool) error
+	// DeleteNodePool removes the node pool with the given name from the cluster
+	DeleteNodePool(ctx context.Context, id, name string) error
+	// Upgrades lists the version slugs the cluster can be upgraded to
+	Upgrades(ctx context.Context, id string) ([]string, error)
+	// Upgrade the cluster to the given kubernetes version
+	Upgrade(ctx context.Context, id, version string) error
+	// KubeConfig fetches the admin kubeconfig of the cluster
+	KubeConfig(ctx context.Context, id string) (*KubeConfig, error)
+}
+
+// Credentials are what a provider authenticates to the cloud with, they are read from the credential source of a kluster
+type Credentials struct {
+	Token           string
+	SecretUID       types.UID /* UID of the secret or other source the token was read from, empty when there is none */
+	ResourceVersion string    /* Version of the source, the token may have changed when it is different */
+	Kluster         types.UID /* UID of the kluster the credentials were read for */
+}
+
+// Factory builds the providers of one cloud, each of them acts with the credentials of one kluster
+type Factory interface {
+	// For returns a provider that makes its calls with creds
+	For(creds Credentials) (Provider, error)
+}
+
+// Registry maps the spec.provider names to their factories
+type Registry map[string]Factory
+
+// Get the factory registered under name, the empty name selects the default provider
+func (r Registry) Get(name string) (Factory, error) {
+	if name == "" {
+		name = Default
+	}
+	p, ok := r[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownProvider, name)
+	}
+	return p, nil
+}
+
+// Forget drops what the factories keep for the kluster with the given UID, once it was deleted
+func (r Registry) Forget(kluster types.UID) {
+	for _, f := range r {
+		if f, ok := f.(interface{ Forget(types.UID) }); ok {
+			f.Forget(kluster)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package scope
+
+import (
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	klister "kluster/pkg/client/listers/siqi.dev/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelisters "k8s.io/client-go/listers/core/v1"
+)
+
+// The listers of the scope read from the informers of the namespace they are asked for,
+// namespaces outside of the scope look empty
+
+// KlusterLister lists the klusters of all namespaces in the scope
+func (s *Scope) KlusterLister() klister.KlusterLister {
+	return &klusterLister{scope: s}
+}
+
+// GrantLister lists the KlusterReferenceGrants of all namespaces in the scope
+func (s *Scope) GrantLister() klister.KlusterReferenceGrantLister {
+	return &grantLister{scope: s}
+}
+
+// SecretLister lists the secrets of all namespaces in the scope that match the secret selector
+func (s *Scope) SecretLister() corelisters.SecretLister {
+	return &secretLister{scope: s}
+}
+
+type klusterLister struct {
+	scope *Scope
+}
+
+func (l *klusterLister) List(selector labels.Selector) ([]*v1alpha1.Kluster, error) {
+	var all []*v1alpha1.Kluster
+	for _, ns := range l.scope.namespaces {
+		klusters, err := ns.Klusters.Lister().List(selector)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, klusters...)
+	}
+	return all, nil
+}
+
+func (l *klusterLister) Klusters(namespace string) klister.KlusterNamespaceLister {
+	if ns, ok := l.scope.namespace(namespace); ok {
+		return ns.Klusters.Lister().Klusters(namespace)
+	}
+	return klister.NewKlusterLister(l.scope.empty).Klusters(namespace)
+}
+
+type grantLister struct {
+	scope *Scope
+}
+
+func (l *grantLister) List(selector labels.Selector) ([]*v1alpha1.KlusterReferenceGrant, error) {
+	var all []*v1alpha1.KlusterReferenceGrant
+	for _, ns := range l.scope.namespaces {
+		grants, err := ns.Grants.Lister().List(selector)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, grants...)
+	}
+	return all, nil
+}
+
+func (l *grantLister) KlusterReferenceGrants(namespace string) klister.KlusterReferenceGrantNamespaceLister {
+	if ns, ok := l.scope.namespace(namespace); ok {
+		return ns.Grants.Lister().KlusterReferenceGrants(namespace)
+	}
+	return klister.NewKlusterReferenceGrantLister(l.scope.empty).KlusterReferenceGrants(namespace)
+}
+
+type secretLister struct {
+	scope *Scope
+}
+
+func (l *secretLister) List(selector labels.Selector) ([]*corev1.Secret, error) {
+	var all []*corev1.Secret
+	for _, ns := range l.scope.namespaces {
+		secrets, err := ns.Secrets.Lister().List(selector)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, secrets...)
+	}
+	return all, nil
+}
+
+func (l *secretLister) Secrets(namespace string) corelisters.SecretNamespaceLister {
+	if ns, ok := l.scope.namespace(namespace); ok {
+		return ns.Secrets.Lister().Secrets(namespace)
+	}
+	return corelisters.NewSecretLister(l.scope.empty).Secrets(namespace)
+}
@@ -0,0 +1,130 @@
+package scope
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	klientset "kluster/pkg/client/clientset/versioned"
+	kinfFac "kluster/pkg/client/informers/externalversions"
+	kinf "kluster/pkg/client/informers/externalversions/siqi.dev/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Namespace holds the informers of the namespaced resources in one namespace the controller watches
+type Namespace struct {
+	Name     string                             /* Name of the namespace, empty when all namespaces are watched */
+	Klusters kinf.KlusterInformer               /* Klusters of the namespace that match the label selector */
+	Grants   kinf.KlusterReferenceGrantInformer /* Grants to reference the secrets of the namespace */
+	Secrets  coreinformers.SecretInformer       /* Secrets of the namespace that match the secret selector */
+}
+
+// Scope is the namespaces the controller manages klusters in. Each of them has its own informers,
+// so that the controller only needs a Role in them and instances of several teams do not see each other.
+type Scope struct {
+	namespaces  []Namespace
+	credentials kinf.KlusterCredentialsInformer /* The KlusterCredentials are cluster-scoped, they are watched in any scope */
+	factories   []interface{ Start(stopCh <-chan struct{}) }
+	empty       cache.Indexer /* Backs the listers of namespaces outside of the scope */
+}
+
+// New creates the informers of the namespaces, or of all namespaces when there are none.
+// The klusters are resynced every resync and only those matching the label selector are watched,
+// the empty selector matches all of them. Only the secrets matching secretSelector are cached,
+// the empty one caches all secrets of the namespaces.
+func New(klient klientset.Interface, client kubernetes.Interface, namespaces []string, selector, secretSelector string, resync time.Duration) *Scope {
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+	withSelector := kinfFac.WithTweakListOptions(func(opts *metav1.ListOptions) {
+		opts.LabelSelector = selector
+	})
+	withSecretSelector := kubeinformers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+		opts.LabelSelector = secretSelector
+	})
+
+	s := &Scope{empty: cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})}
+	for _, ns := range namespaces {
+		// The selector only applies to the klusters, so the grants have a factory of their own
+		klusters := kinfFac.NewSharedInformerFactoryWithOptions(klient, resync, kinfFac.WithNamespace(ns), withSelector)
+		grants := kinfFac.NewSharedInformerFactoryWithOptions(klient, 0, kinfFac.WithNamespace(ns))
+		secrets := kubeinformers.NewSharedInformerFactoryWithOptions(client, 0, kubeinformers.WithNamespace(ns), withSecretSelector)
+		s.namespaces = append(s.namespaces, Namespace{
+			Name:     ns,
+			Klusters: klusters.Siqi().V1alpha1().Klusters(),
+			Grants:   grants.Siqi().V1alpha1().KlusterReferenceGrants(),
+			Secrets:  secrets.Core().V1().Secrets(),
+		})
+		s.factories = append(s.factories, klusters, grants, secrets)
+	}
+	credentials := kinfFac.NewSharedInformerFactory(klient, 0)
+	s.credentials = credentials.Siqi().V1alpha1().KlusterCredentials()
+	s.factories = append(s.factories, credentials)
+	return s
+}
+
+// Start the informers that were requested from the scope until stop is closed
+func (s *Scope) Start(stop <-chan struct{}) {
+	for _, f := range s.factories {
+		f.Start(stop)
+	}
+}
+
+// Namespaces are the watched namespaces with their informers
+func (s *Scope) Namespaces() []Namespace {
+	return s.namespaces
+}
+
+// Credentials is the informer of the cluster-scoped KlusterCredentials
+func (s *Scope) Credentials() kinf.KlusterCredentialsInformer {
+	return s.credentials
+}
+
+// Contains reports whether the namespace is watched
+func (s *Scope) Contains(namespace string) bool {
+	_, ok := s.namespace(namespace)
+	return ok
+}
+
+// The informers that watch the namespace
+func (s *Scope) namespace(namespace string) (Namespace, bool) {
+	for _, ns := range s.namespaces {
+		if ns.Name == metav1.NamespaceAll || ns.Name == namespace {
+			return ns, true
+		}
+	}
+	return Namespace{}, false
+}
+
+// Synced reports which informer of the scope has not synced yet
+func (s *Scope) Synced() error {
+	for _, ns := range s.namespaces {
+		name := ns.Name
+		if name == metav1.NamespaceAll {
+			name = "all namespaces"
+		}
+		if !ns.Klusters.Informer().HasSynced() {
+			return fmt.Errorf("kluster informer of %s has not synced", name)
+		}
+		if !ns.Grants.Informer().HasSynced() {
+			return fmt.Errorf("KlusterReferenceGrant informer of %s has not synced", name)
+		}
+		if !ns.Secrets.Informer().HasSynced() {
+			return fmt.Errorf("secret informer of %s has not synced", name)
+		}
+	}
+	if !s.credentials.Informer().HasSynced() {
+		return errors.New("KlusterCredentials informer has not synced")
+	}
+	return nil
+}
+
+// HasSynced reports whether all informers of the scope have synced
+func (s *Scope) HasSynced() bool {
+	return s.Synced() == nil
+}
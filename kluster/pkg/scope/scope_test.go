@@ -0,0 +1,178 @@
+package scope
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	kfake "kluster/pkg/client/clientset/versioned/fake"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func kluster(namespace, name string, labels map[string]string) *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+func secret(namespace, name string, labels map[string]string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+}
+
+// Scope over fake clientsets holding the objects, with its informers started and synced
+func newSynced(t *testing.T, namespaces []string, selector, secretSelector string, objects ...runtime.Object) *Scope {
+	var klusters, secrets []runtime.Object
+	for _, obj := range objects {
+		if _, ok := obj.(*corev1.Secret); ok {
+			secrets = append(secrets, obj)
+			continue
+		}
+		klusters = append(klusters, obj)
+	}
+	s := New(kfake.NewSimpleClientset(klusters...), kubefake.NewSimpleClientset(secrets...), namespaces, selector, secretSelector, 0)
+	// Request the informers, the factories only start informers that were asked for
+	for _, ns := range s.Namespaces() {
+		ns.Klusters.Informer()
+		ns.Grants.Informer()
+		ns.Secrets.Informer()
+	}
+	s.Credentials().Informer()
+
+	stop := make(chan struct{})
+	t.Cleanup(func() { close(stop) })
+	s.Start(stop)
+	if !cache.WaitForCacheSync(stop, s.HasSynced) {
+		t.Fatal("the informers of the scope did not sync")
+	}
+	return s
+}
+
+func klusterNames(klusters []*v1alpha1.Kluster) []string {
+	names := []string{}
+	for _, k := range klusters {
+		names = append(names, k.Namespace+"/"+k.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestNew(t *testing.T) {
+	s := New(kfake.NewSimpleClientset(), kubefake.NewSimpleClientset(), []string{"team-a", "team-b"}, "", "", time.Minute)
+	if n := len(s.Namespaces()); n != 2 {
+		t.Fatalf("Namespaces() has %d namespaces, want 2", n)
+	}
+	for i, want := range []string{"team-a", "team-b"} {
+		if name := s.Namespaces()[i].Name; name != want {
+			t.Errorf("namespace %d = %q, want %q", i, name, want)
+		}
+	}
+
+	all := New(kfake.NewSimpleClientset(), kubefake.NewSimpleClientset(), nil, "", "", time.Minute)
+	if n := len(all.Namespaces()); n != 1 || all.Namespaces()[0].Name != metav1.NamespaceAll {
+		t.Errorf("Namespaces() without namespaces = %+v, want only all namespaces", all.Namespaces())
+	}
+	if all.Synced() == nil {
+		t.Error("Synced() of informers that were not started reports no error")
+	}
+}
+
+func TestContains(t *testing.T) {
+	s := New(kfake.NewSimpleClientset(), kubefake.NewSimpleClientset(), []string{"team-a", "team-b"}, "", "", 0)
+	all := New(kfake.NewSimpleClientset(), kubefake.NewSimpleClientset(), nil, "", "", 0)
+
+	tests := []struct {
+		namespace string
+		want      bool
+		wantAll   bool
+	}{
+		{namespace: "team-a", want: true, wantAll: true},
+		{namespace: "team-b", want: true, wantAll: true},
+		{namespace: "team-c", want: false, wantAll: true},
+	}
+	for _, tt := range tests {
+		if got := s.Contains(tt.namespace); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.namespace, got, tt.want)
+		}
+		if got := all.Contains(tt.namespace); got != tt.wantAll {
+			t.Errorf("Contains(%q) of all namespaces = %v, want %v", tt.namespace, got, tt.wantAll)
+		}
+	}
+}
+
+func TestListers(t *testing.T) {
+	objects := []runtime.Object{
+		kluster("team-a", "a", nil),
+		kluster("team-b", "b", map[string]string{"env": "prod"}),
+		kluster("team-c", "c", map[string]string{"env": "prod"}),
+		secret("team-a", "token", map[string]string{"kluster.siqi.dev/token": "true"}),
+		secret("team-a", "other", nil),
+		secret("team-c", "token", map[string]string{"kluster.siqi.dev/token": "true"}),
+	}
+
+	tests := []struct {
+		name           string
+		namespaces     []string
+		selector       string
+		secretSelector string
+		wantKlusters   []string
+		wantSecrets    int
+		found          map[string]bool /* Whether the namespace lister finds the kluster namespace/name */
+	}{
+		{
+			name:         "namespaces",
+			namespaces:   []string{"team-a", "team-b"},
+			wantKlusters: []string{"team-a/a", "team-b/b"},
+			wantSecrets:  2,
+			found:        map[string]bool{"team-a/a": true, "team-b/b": true, "team-c/c": false},
+		},
+		{
+			name:         "all namespaces",
+			wantKlusters: []string{"team-a/a", "team-b/b", "team-c/c"},
+			wantSecrets:  3,
+			found:        map[string]bool{"team-a/a": true, "team-b/b": true, "team-c/c": true},
+		},
+		{
+			name:           "selectors",
+			namespaces:     []string{"team-a", "team-b"},
+			selector:       "env=prod",
+			secretSelector: "kluster.siqi.dev/token=true",
+			wantKlusters:   []string{"team-b/b"},
+			wantSecrets:    1,
+			found:          map[string]bool{"team-a/a": false, "team-b/b": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSynced(t, tt.namespaces, tt.selector, tt.secretSelector, objects...)
+
+			klusters, err := s.KlusterLister().List(labels.Everything())
+			if err != nil {
+				t.Fatalf("listing the klusters: %v", err)
+			}
+			if got := klusterNames(klusters); !reflect.DeepEqual(got, tt.wantKlusters) {
+				t.Errorf("klusters = %v, want %v", got, tt.wantKlusters)
+			}
+			secrets, err := s.SecretLister().List(labels.Everything())
+			if err != nil {
+				t.Fatalf("listing the secrets: %v", err)
+			}
+			if len(secrets) != tt.wantSecrets {
+				t.Errorf("listed %d secrets, want %d", len(secrets), tt.wantSecrets)
+			}
+			for key, want := range tt.found {
+				namespace, name, _ := cache.SplitMetaNamespaceKey(key)
+				_, err := s.KlusterLister().Klusters(namespace).Get(name)
+				if found := err == nil; found != want {
+					t.Errorf("Get(%s) found = %v, want %v: %v", key, found, want, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/apis/siqi.dev/v1beta1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+// Path the API server sends conversion reviews to, it has to match spec.conversion of the CRD
+const ConvertPath = "/convert"
+
+// Convert the klusters of a ConversionReview to the desired version
+func serveConvert(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("expected a ConversionReview with a request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := &apiextensionsv1.ConversionResponse{
+		UID:    review.Request.UID,
+		Result: metav1.Status{Status: metav1.StatusSuccess},
+	}
+	for _, obj := range review.Request.Objects {
+		converted, err := convert(obj.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			klog.Errorf("error %s, converting kluster to %s\n", err.Error(), review.Request.DesiredAPIVersion)
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+
+	review.APIVersion = apiextensionsv1.SchemeGroupVersion.String()
+	review.Kind = "ConversionReview"
+	review.Request = nil
+	review.Response = response
+
+	out, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+// Convert a serialized kluster to the desired version through the v1beta1 hub
+func convert(raw []byte, desired string) ([]byte, error) {
+	meta := metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, &meta); err != nil {
+		return nil, err
+	}
+	if meta.APIVersion == desired {
+		return raw, nil
+	}
+
+	hub := &v1beta1.Kluster{}
+	switch meta.APIVersion {
+	case v1beta1.SchemeGroupVersion.String():
+		if err := json.Unmarshal(raw, hub); err != nil {
+			return nil, err
+		}
+	case v1alpha1.SchemeGroupVersion.String():
+		spoke := &v1alpha1.Kluster{}
+		if err := json.Unmarshal(raw, spoke); err != nil {
+			return nil, err
+		}
+		if err := spoke.ConvertTo(hub); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported version %q", meta.APIVersion)
+	}
+
+	switch desired {
+	case v1beta1.SchemeGroupVersion.String():
+		return json.Marshal(hub)
+	case v1alpha1.SchemeGroupVersion.String():
+		spoke := &v1alpha1.Kluster{}
+		if err := spoke.ConvertFrom(hub); err != nil {
+			return nil, err
+		}
+		return json.Marshal(spoke)
+	}
+	return nil, fmt.Errorf("unsupported version %q", desired)
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	"kluster/pkg/apis/siqi.dev/v1beta1"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func raw(t *testing.T, obj interface{}) runtime.RawExtension {
+	t.Helper()
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return runtime.RawExtension{Raw: data}
+}
+
+// Post the review to serveConvert and decode the review it answers with
+func postReview(t *testing.T, review *apiextensionsv1.ConversionReview) *apiextensionsv1.ConversionReview {
+	t.Helper()
+	body, err := json.Marshal(review)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	serveConvert(rec, httptest.NewRequest(http.MethodPost, ConvertPath, bytes.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status code = %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	out := &apiextensionsv1.ConversionReview{}
+	if err := json.Unmarshal(rec.Body.Bytes(), out); err != nil {
+		t.Fatalf("decoding the response: %v", err)
+	}
+	if out.Response == nil {
+		t.Fatal("the review has no response")
+	}
+	return out
+}
+
+func TestServeConvert(t *testing.T) {
+	alpha := &v1alpha1.Kluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Kluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a"},
+		Spec: v1alpha1.KlusterSpec{
+			Name:        "a",
+			TokenSecret: "team-b/dosecret",
+			NodePools:   []v1alpha1.NodePool{{Name: "a", Size: "s", Count: 2, AutoScale: true, MinNodes: 1, MaxNodes: 3}},
+		},
+	}
+	beta := &v1beta1.Kluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1beta1.SchemeGroupVersion.String(), Kind: "Kluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "team-a"},
+		Spec: v1beta1.KlusterSpec{
+			Name:           "b",
+			TokenSecretRef: &v1beta1.SecretReference{Namespace: "team-c", Name: "dosecret"},
+			NodePools:      []v1beta1.NodePool{{Name: "b", Size: "s", Count: 1}},
+		},
+	}
+
+	review := postReview(t, &apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               "review-1",
+			DesiredAPIVersion: v1beta1.SchemeGroupVersion.String(),
+			Objects:           []runtime.RawExtension{raw(t, alpha), raw(t, beta)},
+		},
+	})
+
+	resp := review.Response
+	if resp.UID != "review-1" {
+		t.Errorf("response UID = %q, want review-1", resp.UID)
+	}
+	if resp.Result.Status != metav1.StatusSuccess {
+		t.Fatalf("result = %+v, want success", resp.Result)
+	}
+	if review.Request != nil {
+		t.Error("the response still holds the request")
+	}
+	if len(resp.ConvertedObjects) != 2 {
+		t.Fatalf("%d converted objects, want 2", len(resp.ConvertedObjects))
+	}
+
+	got := []v1beta1.Kluster{}
+	for _, obj := range resp.ConvertedObjects {
+		k := v1beta1.Kluster{}
+		if err := json.Unmarshal(obj.Raw, &k); err != nil {
+			t.Fatalf("decoding converted object: %v", err)
+		}
+		if k.APIVersion != v1beta1.SchemeGroupVersion.String() {
+			t.Errorf("converted %s has apiVersion %q, want %s", k.Name, k.APIVersion, v1beta1.SchemeGroupVersion)
+		}
+		got = append(got, k)
+	}
+	// Objects are answered in the order of the request
+	if got[0].Name != "a" || got[1].Name != "b" {
+		t.Fatalf("converted objects %s, %s, want a, b", got[0].Name, got[1].Name)
+	}
+	if ref := got[0].Spec.TokenSecretRef; ref == nil || ref.Namespace != "team-b" || ref.Name != "dosecret" {
+		t.Errorf("tokenSecretRef of a = %+v, want team-b/dosecret", ref)
+	}
+	if as := got[0].Spec.NodePools[0].Autoscaling; as == nil || as.MinNodes != 1 || as.MaxNodes != 3 {
+		t.Errorf("autoscaling of a = %+v, want 1 to 3 nodes", as)
+	}
+	if ref := got[1].Spec.TokenSecretRef; ref == nil || ref.Namespace != "team-c" {
+		t.Errorf("tokenSecretRef of b = %+v, want it unchanged", ref)
+	}
+}
+
+func TestServeConvertUnsupportedVersion(t *testing.T) {
+	review := postReview(t, &apiextensionsv1.ConversionReview{
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               "review-2",
+			DesiredAPIVersion: "siqi.dev/v2",
+			Objects:           []runtime.RawExtension{raw(t, &v1alpha1.Kluster{TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.SchemeGroupVersion.String(), Kind: "Kluster"}})},
+		},
+	})
+	if review.Response.UID != "review-2" {
+		t.Errorf("response UID = %q, want review-2", review.Response.UID)
+	}
+	if review.Response.Result.Status != metav1.StatusFailure {
+		t.Errorf("result = %+v, want failure", review.Response.Result)
+	}
+	if len(review.Response.ConvertedObjects) != 0 {
+		t.Errorf("%d converted objects of a failed conversion, want none", len(review.Response.ConvertedObjects))
+	}
+}
+
+func TestServeConvertWithoutRequest(t *testing.T) {
+	rec := httptest.NewRecorder()
+	serveConvert(rec, httptest.NewRequest(http.MethodPost, ConvertPath, bytes.NewReader([]byte(`{"kind":"ConversionReview"}`))))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status code = %d, want 400", rec.Code)
+	}
+}
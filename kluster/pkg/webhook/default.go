@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"encoding/json"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/klog/v2"
+)
+
+// Defaults are filled into klusters that leave the fields empty, they are set for the whole controller
+type Defaults struct {
+	Region   string            /* Region of klusters without spec.region */
+	Version  string            /* Version of klusters without spec.version, "latest" lets the provider pick the newest */
+	NodeSize string            /* Size of node pools without a size */
+	NodePool v1alpha1.NodePool /* Node pool of klusters without any node pools */
+}
+
+// Apply the defaults to the spec of a created kluster
+func (d Defaults) Apply(kluster *v1alpha1.Kluster) {
+	spec := &kluster.Spec
+	if spec.Name == "" {
+		spec.Name = kluster.Name
+	}
+	if spec.Region == "" {
+		spec.Region = d.Region
+	}
+	if spec.Version == "" {
+		spec.Version = d.Version
+	}
+	if len(spec.NodePools) == 0 && d.NodePool.Name != "" {
+		spec.NodePools = []v1alpha1.NodePool{d.NodePool}
+	}
+	d.applyNodeSize(spec)
+}
+
+// Fill the size of node pools without one, also those added by an update
+func (d Defaults) applyNodeSize(spec *v1alpha1.KlusterSpec) {
+	for i := range spec.NodePools {
+		if spec.NodePools[i].Size == "" {
+			spec.NodePools[i].Size = d.NodeSize
+		}
+	}
+}
+
+// Default a created or updated kluster, the changes are returned as a JSON patch of the spec
+func (d Defaults) admit(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	kluster := &v1alpha1.Kluster{}
+	if err := json.Unmarshal(req.Object.Raw, kluster); err != nil {
+		return errored(err)
+	}
+
+	// Klusters that are being deleted only have their finalizers removed
+	if kluster.DeletionTimestamp != nil {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	original := kluster.Spec.DeepCopy()
+	switch req.Operation {
+	case admissionv1.Create:
+		d.Apply(kluster)
+	case admissionv1.Update:
+		// The other defaults are only set on create, e.g. emptied node pools are left to the validation
+		// instead of replacing the pools of the cluster with the default pool
+		d.applyNodeSize(&kluster.Spec)
+	}
+	if equality.Semantic.DeepEqual(original, &kluster.Spec) {
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	// add replaces the spec if it exists and creates it otherwise
+	patch, err := json.Marshal([]map[string]interface{}{
+		{"op": "add", "path": "/spec", "value": kluster.Spec},
+	})
+	if err != nil {
+		return errored(err)
+	}
+	klog.Infof("defaulting the spec of kluster %s/%s\n", req.Namespace, req.Name)
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: patch, PatchType: &patchType}
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestDefaultsAdmit(t *testing.T) {
+	d := Defaults{
+		Region:   "nyc1",
+		Version:  "latest",
+		NodeSize: "s-2vcpu-2gb",
+		NodePool: v1alpha1.NodePool{Name: "default-pool", Size: "s-2vcpu-2gb", Count: 3},
+	}
+
+	tests := []struct {
+		name      string
+		operation admissionv1.Operation
+		spec      v1alpha1.KlusterSpec
+		want      *v1alpha1.KlusterSpec /* Spec after the patch, nil when nothing is patched */
+	}{
+		{
+			name:      "create fills in the empty fields",
+			operation: admissionv1.Create,
+			want: &v1alpha1.KlusterSpec{
+				Name:      "k",
+				Region:    "nyc1",
+				Version:   "latest",
+				NodePools: []v1alpha1.NodePool{{Name: "default-pool", Size: "s-2vcpu-2gb", Count: 3}},
+			},
+		},
+		{
+			name:      "update leaves emptied node pools to the validation",
+			operation: admissionv1.Update,
+			spec:      v1alpha1.KlusterSpec{Name: "k", Region: "nyc1", Version: "latest"},
+		},
+		{
+			name:      "update fills in the size of added node pools",
+			operation: admissionv1.Update,
+			spec:      v1alpha1.KlusterSpec{Name: "k", Region: "nyc1", NodePools: []v1alpha1.NodePool{{Name: "b", Count: 1}}},
+			want:      &v1alpha1.KlusterSpec{Name: "k", Region: "nyc1", NodePools: []v1alpha1.NodePool{{Name: "b", Size: "s-2vcpu-2gb", Count: 1}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(&v1alpha1.Kluster{ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a"}, Spec: tt.spec})
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp := d.admit(&admissionv1.AdmissionRequest{Operation: tt.operation, Object: runtime.RawExtension{Raw: raw}})
+			if !resp.Allowed {
+				t.Fatalf("admit() did not allow the kluster: %+v", resp.Result)
+			}
+			if tt.want == nil {
+				if resp.Patch != nil {
+					t.Errorf("admit() patched the kluster: %s", resp.Patch)
+				}
+				return
+			}
+
+			patch := []struct {
+				Op    string               `json:"op"`
+				Path  string               `json:"path"`
+				Value v1alpha1.KlusterSpec `json:"value"`
+			}{}
+			if err := json.Unmarshal(resp.Patch, &patch); err != nil {
+				t.Fatalf("decoding the patch %s: %v", resp.Patch, err)
+			}
+			if len(patch) != 1 || patch[0].Path != "/spec" {
+				t.Fatalf("patch = %s, want one operation on /spec", resp.Patch)
+			}
+			got, _ := json.Marshal(patch[0].Value)
+			want, _ := json.Marshal(tt.want)
+			if string(got) != string(want) {
+				t.Errorf("spec after the patch = %s, want %s", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+	klister "kluster/pkg/client/listers/siqi.dev/v1alpha1"
+	"kluster/pkg/credentials"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/klog/v2"
+)
+
+// Paths the API server sends kluster reviews to, they have to match the webhook configurations
+const (
+	DefaultPath  = "/default-siqi-dev-v1alpha1-kluster"
+	ValidatePath = "/validate-siqi-dev-v1alpha1-kluster"
+)
+
+// Server serves the admission webhooks of klusters over TLS
+type Server struct {
+	Addr     string                              /* Address to listen on, e.g. :9443 */
+	CertDir  string                              /* Directory with tls.crt and tls.key */
+	Defaults Defaults                            /* Defaults filled in by the mutating webhook */
+	Grants   klister.KlusterReferenceGrantLister /* Grants of secrets in other namespaces, nil skips the check */
+	Manages  func(namespace string) bool         /* Whether the klusters of the namespace are managed by this controller, nil manages all */
+}
+
+// Start serving the webhooks, it blocks until the server fails
+func (s *Server) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(DefaultPath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, s.Defaults.admit)
+	})
+	mux.HandleFunc(ValidatePath, func(w http.ResponseWriter, r *http.Request) {
+		serve(w, r, s.validate)
+	})
+	mux.HandleFunc(ConvertPath, serveConvert)
+
+	klog.Infof("serving webhooks on %s\n", s.Addr)
+	server := &http.Server{Addr: s.Addr, Handler: mux}
+	return server.ListenAndServeTLS(filepath.Join(s.CertDir, "tls.crt"), filepath.Join(s.CertDir, "tls.key"))
+}
+
+// Decode the AdmissionReview of the request, let admit decide and write the review back
+func serve(w http.ResponseWriter, r *http.Request, admit func(*admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("expected an AdmissionReview with a request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The API server only accepts a review of the same version in return
+	review.APIVersion = admissionv1.SchemeGroupVersion.String()
+	review.Kind = "AdmissionReview"
+	response := admit(review.Request)
+	response.UID = review.Request.UID
+	review.Response = response
+	review.Request = nil
+
+	out, err := json.Marshal(review)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(out)
+}
+
+// Validate a created or updated kluster
+func (s *Server) validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	kluster := &v1alpha1.Kluster{}
+	if err := json.Unmarshal(req.Object.Raw, kluster); err != nil {
+		return errored(err)
+	}
+
+	var errs field.ErrorList
+	switch req.Operation {
+	case admissionv1.Create:
+		errs = ValidateKluster(kluster)
+		errs = append(errs, s.validateTokenSecretGrant(req.Namespace, kluster)...)
+	case admissionv1.Update:
+		old := &v1alpha1.Kluster{}
+		if err := json.Unmarshal(req.OldObject.Raw, old); err != nil {
+			return errored(err)
+		}
+		errs = ValidateKlusterUpdate(kluster, old)
+		// A grant that was removed later only stops the reconciles, it does not block other changes
+		if kluster.Spec.TokenSecret != old.Spec.TokenSecret {
+			errs = append(errs, s.validateTokenSecretGrant(req.Namespace, kluster)...)
+		}
+	}
+
+	if len(errs) > 0 {
+		klog.Infof("rejecting kluster %s/%s: %s\n", req.Namespace, req.Name, errs.ToAggregate().Error())
+		status := apierrors.NewInvalid(v1alpha1.SchemeGroupVersion.WithKind("Kluster").GroupKind(), kluster.Name, errs).ErrStatus
+		return &admissionv1.AdmissionResponse{Allowed: false, Result: &status}
+	}
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+// Klusters may only reference a token secret of another namespace that a KlusterReferenceGrant allows
+func (s *Server) validateTokenSecretGrant(namespace string, kluster *v1alpha1.Kluster) field.ErrorList {
+	path := field.NewPath("spec", "tokenSecret")
+	secretNamespace, name, ok := strings.Cut(kluster.Spec.TokenSecret, "/")
+	// The klusters of other namespaces are checked by the controller that manages them
+	if s.Grants == nil || !ok || (s.Manages != nil && !s.Manages(namespace)) {
+		return nil
+	}
+	allowed, err := credentials.SecretReferenceAllowed(s.Grants, namespace, secretNamespace, name)
+	if err != nil {
+		return field.ErrorList{field.InternalError(path, err)}
+	}
+	if !allowed {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("no KlusterReferenceGrant of namespace %s allows klusters of namespace %s to reference the secret", secretNamespace, namespace))}
+	}
+	return nil
+}
+
+// Response for a review that could not be decoded
+func errored(err error) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusBadRequest,
+			Reason:  metav1.StatusReasonBadRequest,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"regexp"
+	"strings"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apimachineryvalidation "k8s.io/apimachinery/pkg/api/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Region slugs look like nyc1 or sfo3
+var regionSlug = regexp.MustCompile(`^[a-z]+[0-9]+$`)
+
+// ValidateKluster checks the spec of a kluster that is created
+func ValidateKluster(kluster *v1alpha1.Kluster) field.ErrorList {
+	return validateSpec(&kluster.Spec, field.NewPath("spec"))
+}
+
+// ValidateKlusterUpdate checks the spec of an updated kluster and that immutable fields did not change
+func ValidateKlusterUpdate(kluster, old *v1alpha1.Kluster) field.ErrorList {
+	// Klusters that are being deleted only have their finalizers removed
+	if kluster.DeletionTimestamp != nil {
+		return nil
+	}
+	// Metadata changes like adding the finalizer must not be blocked by klusters created before validation
+	if equality.Semantic.DeepEqual(kluster.Spec, old.Spec) {
+		return nil
+	}
+
+	spec := field.NewPath("spec")
+	errs := validateSpec(&kluster.Spec, spec)
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(kluster.Spec.Name, old.Spec.Name, spec.Child("name"))...)
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(kluster.Spec.Region, old.Spec.Region, spec.Child("region"))...)
+	errs = append(errs, apimachineryvalidation.ValidateImmutableField(kluster.Spec.Provider, old.Spec.Provider, spec.Child("provider"))...)
+
+	// Node pools can not be resized in place, they have to be replaced by a pool with another name
+	sizes := map[string]string{}
+	for _, np := range old.Spec.NodePools {
+		sizes[np.Name] = np.Size
+	}
+	for i, np := range kluster.Spec.NodePools {
+		if size, ok := sizes[np.Name]; ok && size != "" && np.Size != size {
+			errs = append(errs, field.Invalid(spec.Child("nodePools").Index(i).Child("size"), np.Size, "size is immutable, replace the node pool to change it"))
+		}
+	}
+	return errs
+}
+
+func validateSpec(spec *v1alpha1.KlusterSpec, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+
+	if spec.Name == "" {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+
+	switch {
+	case spec.Region == "":
+		errs = append(errs, field.Required(path.Child("region"), ""))
+	case !regionSlug.MatchString(spec.Region):
+		errs = append(errs, field.Invalid(path.Child("region"), spec.Region, "must be a region slug like nyc1"))
+	}
+
+	// Klusters without a token secret or credentials use the default token of the controller
+	switch {
+	case spec.TokenSecret != "" && spec.Credentials != "":
+		errs = append(errs, field.Forbidden(path.Child("credentials"), "may not be set together with tokenSecret"))
+	case spec.TokenSecret != "":
+		errs = append(errs, validateTokenSecret(spec.TokenSecret, path.Child("tokenSecret"))...)
+	case spec.Credentials != "":
+		for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(spec.Credentials, false) {
+			errs = append(errs, field.Invalid(path.Child("credentials"), spec.Credentials, msg))
+		}
+	}
+	if spec.TokenSecretKey != "" && spec.TokenSecret == "" {
+		errs = append(errs, field.Forbidden(path.Child("tokenSecretKey"), "may only be set together with tokenSecret"))
+	}
+
+	switch spec.DriftPolicy {
+	case "", v1alpha1.DriftPolicyCorrect, v1alpha1.DriftPolicyReport:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("driftPolicy"), spec.DriftPolicy, []string{v1alpha1.DriftPolicyCorrect, v1alpha1.DriftPolicyReport}))
+	}
+
+	pools := path.Child("nodePools")
+	if len(spec.NodePools) == 0 {
+		errs = append(errs, field.Required(pools, "at least one node pool is needed"))
+	}
+	names := map[string]bool{}
+	for i, np := range spec.NodePools {
+		p := pools.Index(i)
+		switch {
+		case np.Name == "":
+			errs = append(errs, field.Required(p.Child("name"), ""))
+		case names[np.Name]:
+			errs = append(errs, field.Duplicate(p.Child("name"), np.Name))
+		}
+		names[np.Name] = true
+		if np.Size == "" {
+			errs = append(errs, field.Required(p.Child("size"), ""))
+		}
+		if np.Count < 1 {
+			errs = append(errs, field.Invalid(p.Child("count"), np.Count, "must be at least 1"))
+		}
+		if np.AutoScale && np.MaxNodes < np.MinNodes {
+			errs = append(errs, field.Invalid(p.Child("maxNodes"), np.MaxNodes, "must not be less than minNodes"))
+		}
+		if np.AutoScale && np.MaxNodes < 1 {
+			errs = append(errs, field.Invalid(p.Child("maxNodes"), np.MaxNodes, "must be at least 1 when autoScale is set"))
+		}
+	}
+
+	return errs
+}
+
+// The token secret is referenced as namespace/name
+func validateTokenSecret(ref string, path *field.Path) field.ErrorList {
+	namespace, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return field.ErrorList{field.Invalid(path, ref, "must be in the form namespace/name")}
+	}
+	errs := field.ErrorList{}
+	for _, msg := range apimachineryvalidation.ValidateNamespaceName(namespace, false) {
+		errs = append(errs, field.Invalid(path, ref, "namespace "+msg))
+	}
+	for _, msg := range apimachineryvalidation.NameIsDNSSubdomain(name, false) {
+		errs = append(errs, field.Invalid(path, ref, "name "+msg))
+	}
+	return errs
+}
@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+
+	"kluster/pkg/apis/siqi.dev/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func validKluster() *v1alpha1.Kluster {
+	return &v1alpha1.Kluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "k", Namespace: "team-a"},
+		Spec: v1alpha1.KlusterSpec{
+			Name:      "k",
+			Region:    "nyc1",
+			Version:   "1.28.2-do.0",
+			Provider:  "digitalocean",
+			NodePools: []v1alpha1.NodePool{{Name: "a", Size: "s-1vcpu-2gb", Count: 2}},
+		},
+	}
+}
+
+// The fields of the errors, in order
+func errorFields(errs field.ErrorList) []string {
+	fields := []string{}
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	return fields
+}
+
+func TestValidateKluster(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(spec *v1alpha1.KlusterSpec)
+		want   []string /* Fields with errors */
+	}{
+		{
+			name:   "valid",
+			change: func(spec *v1alpha1.KlusterSpec) {},
+			want:   []string{},
+		},
+		{
+			name:   "missing region",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.Region = "" },
+			want:   []string{"spec.region"},
+		},
+		{
+			name:   "invalid region",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.Region = "New York" },
+			want:   []string{"spec.region"},
+		},
+		{
+			name:   "no node pools",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.NodePools = nil },
+			want:   []string{"spec.nodePools"},
+		},
+		{
+			name: "duplicate pool names",
+			change: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools = append(spec.NodePools, v1alpha1.NodePool{Name: "a", Size: "s-2vcpu-4gb", Count: 1})
+			},
+			want: []string{"spec.nodePools[1].name"},
+		},
+		{
+			name: "autoscale min greater than max",
+			change: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools[0].AutoScale, spec.NodePools[0].MinNodes, spec.NodePools[0].MaxNodes = true, 4, 2
+			},
+			want: []string{"spec.nodePools[0].maxNodes"},
+		},
+		{
+			name: "min greater than max without autoscale",
+			change: func(spec *v1alpha1.KlusterSpec) {
+				spec.NodePools[0].MinNodes, spec.NodePools[0].MaxNodes = 4, 2
+			},
+			want: []string{},
+		},
+		{
+			name:   "no nodes",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.NodePools[0].Count = 0 },
+			want:   []string{"spec.nodePools[0].count"},
+		},
+		{
+			name:   "token secret without namespace",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.TokenSecret = "dosecret" },
+			want:   []string{"spec.tokenSecret"},
+		},
+		{
+			name: "token secret and credentials",
+			change: func(spec *v1alpha1.KlusterSpec) {
+				spec.TokenSecret, spec.Credentials = "team-a/dosecret", "team"
+			},
+			want: []string{"spec.credentials"},
+		},
+		{
+			name:   "unknown drift policy",
+			change: func(spec *v1alpha1.KlusterSpec) { spec.DriftPolicy = "Ignore" },
+			want:   []string{"spec.driftPolicy"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kluster := validKluster()
+			tt.change(&kluster.Spec)
+			if got := errorFields(ValidateKluster(kluster)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors of the fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKlusterUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(kluster *v1alpha1.Kluster)
+		want   []string /* Fields with errors */
+	}{
+		{
+			name:   "scale a node pool",
+			change: func(kluster *v1alpha1.Kluster) { kluster.Spec.NodePools[0].Count = 5 },
+			want:   []string{},
+		},
+		{
+			name:   "immutable name",
+			change: func(kluster *v1alpha1.Kluster) { kluster.Spec.Name = "other" },
+			want:   []string{"spec.name"},
+		},
+		{
+			name:   "immutable region",
+			change: func(kluster *v1alpha1.Kluster) { kluster.Spec.Region = "sfo3" },
+			want:   []string{"spec.region"},
+		},
+		{
+			name:   "immutable provider",
+			change: func(kluster *v1alpha1.Kluster) { kluster.Spec.Provider = "fake" },
+			want:   []string{"spec.provider"},
+		},
+		{
+			name:   "immutable node pool size",
+			change: func(kluster *v1alpha1.Kluster) { kluster.Spec.NodePools[0].Size = "s-4vcpu-8gb" },
+			want:   []string{"spec.nodePools[0].size"},
+		},
+		{
+			name: "replace a node pool to change the size",
+			change: func(kluster *v1alpha1.Kluster) {
+				kluster.Spec.NodePools = []v1alpha1.NodePool{{Name: "b", Size: "s-4vcpu-8gb", Count: 2}}
+			},
+			want: []string{},
+		},
+		{
+			name: "deleting kluster",
+			change: func(kluster *v1alpha1.Kluster) {
+				now := metav1.Now()
+				kluster.DeletionTimestamp = &now
+				kluster.Spec.Region = "sfo3"
+			},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old := validKluster()
+			kluster := validKluster()
+			tt.change(kluster)
+			if got := errorFields(ValidateKlusterUpdate(kluster, old)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors of the fields %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKlusterUpdateOfInvalidKluster(t *testing.T) {
+	// Klusters created before the webhook may be invalid, changing only their metadata is allowed
+	old := validKluster()
+	old.Spec.Region = ""
+	kluster := old.DeepCopy()
+	kluster.Finalizers = []string{"siqi.dev/kluster"}
+	if errs := ValidateKlusterUpdate(kluster, old); len(errs) != 0 {
+		t.Errorf("ValidateKlusterUpdate() = %v, want no errors for a metadata change", errs)
+	}
+}